@@ -0,0 +1,347 @@
+package cmd
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/godamri/helix-cli/internal/certs"
+	"github.com/godamri/helix-cli/internal/manifest"
+	"github.com/godamri/helix-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	certsDir      string
+	certsDays     int
+	certsSANs     []string
+	certsForce    bool
+	certsRotateCA bool
+)
+
+// Dev CA outlives the leaves it signs so 'rotate' doesn't force re-trusting it.
+const caValidity = 10 * 365 * 24 * time.Hour
+
+var certsCmd = &cobra.Command{
+	Use:   "certs",
+	Short: "Manage local mTLS certificates (dev CA, server and client certs)",
+	Long: `Creates the certificates used by HTTP_MTLS_* and GRPC_MTLS_* without openssl.
+
+Certificates are written to ./certs (relative to the project root) and the
+HTTP_MTLS_* / GRPC_MTLS_* paths in .env are updated to point at them.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var certsInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a dev CA plus server and client certificates",
+	Example: `  helix-cli certs init
+  helix-cli certs init --san api.local --san 10.0.0.5`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, service, dir, err := certsTarget()
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(filepath.Join(dir, certs.CACert)); err == nil && !certsForce {
+			return output.Errorf(output.CodeAlreadyExists, "CA already exists in %s (use 'certs rotate', or --force to replace it)", dir)
+		}
+
+		ca, err := certs.NewCA(dir, fmt.Sprintf("Helix Dev CA (%s)", service), caValidity)
+		if err != nil {
+			return output.Wrap(output.CodeIO, err)
+		}
+		recordPair(dir, certs.CACert, certs.CAKey, ca.Cert)
+
+		if err := issueServer(ca, dir, service, certs.ServerCert, certs.ServerKey); err != nil {
+			return err
+		}
+		if err := issueClient(ca, dir, "authorized-client", certs.ClientCert, certs.ClientKey); err != nil {
+			return err
+		}
+		if err := writeCertEnv(root, dir); err != nil {
+			return err
+		}
+
+		printf("\nCertificates are in '%s'. Enable mTLS with HTTP_MTLS_ENABLED=true / GRPC_MTLS_ENABLED=true in .env\n", relTo(root, dir))
+		printf("Test: curl --cert %[1]s/client.crt --key %[1]s/client.key --cacert %[1]s/ca.crt https://localhost:<HTTP_PUBLIC_PORT>/health\n", relTo(root, dir))
+		report.Next("Set HTTP_MTLS_ENABLED=true and/or GRPC_MTLS_ENABLED=true in .env")
+		return nil
+	},
+}
+
+var certsIssueCmd = &cobra.Command{
+	Use:   "issue [server|client] [name]",
+	Short: "Issue a server or client certificate signed by the dev CA",
+	Example: `  helix-cli certs issue server
+  helix-cli certs issue client svc-billing   # writes certs/svc-billing.crt`,
+	Args:      cobra.RangeArgs(1, 2),
+	ValidArgs: []string{"server", "client"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, service, dir, err := certsTarget()
+		if err != nil {
+			return err
+		}
+		ca, err := loadCA(dir)
+		if err != nil {
+			return err
+		}
+
+		kind := args[0]
+		name := ""
+		if len(args) > 1 {
+			name = args[1]
+		}
+
+		switch kind {
+		case "server":
+			certFile, keyFile := certs.ServerCert, certs.ServerKey
+			if name != "" {
+				certFile, keyFile = name+".crt", name+".key"
+			}
+			if err := issueServer(ca, dir, service, certFile, keyFile); err != nil {
+				return err
+			}
+			if name == "" {
+				return writeCertEnv(root, dir)
+			}
+			return nil
+		case "client":
+			certFile, keyFile := certs.ClientCert, certs.ClientKey
+			if name != "" {
+				certFile, keyFile = name+".crt", name+".key"
+			} else {
+				name = "authorized-client"
+			}
+			return issueClient(ca, dir, name, certFile, keyFile)
+		}
+		return output.Errorf(output.CodeInvalidArgument, "unknown certificate kind '%s' (expected server or client)", kind)
+	},
+}
+
+var certsRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Re-issue every leaf certificate (and optionally the CA)",
+	Long: `Re-issues each *.crt in the certs directory with the same subject and SANs
+and a fresh key. With --ca the CA is regenerated first; clients must then trust the new ca.crt.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, service, dir, err := certsTarget()
+		if err != nil {
+			return err
+		}
+
+		var ca *certs.Authority
+		if certsRotateCA {
+			ca, err = certs.NewCA(dir, fmt.Sprintf("Helix Dev CA (%s)", service), caValidity)
+			if err != nil {
+				return output.Wrap(output.CodeIO, err)
+			}
+			recordPair(dir, certs.CACert, certs.CAKey, ca.Cert)
+		} else if ca, err = loadCA(dir); err != nil {
+			return err
+		}
+
+		infos, err := certs.Inspect(dir, time.Now())
+		if err != nil {
+			return output.Wrap(output.CodeIO, err)
+		}
+		rotated := 0
+		for _, info := range infos {
+			if info.IsCA || info.File == certs.CACert || info.Subject == "" {
+				continue
+			}
+			keyFile := strings.TrimSuffix(info.File, ".crt") + ".key"
+			req := certs.Request{
+				CommonName: info.Subject,
+				DNSNames:   info.DNSNames,
+				Client:     info.Usage == "client",
+				Validity:   leafValidity(),
+			}
+			for _, ip := range info.IPs {
+				req.IPs = append(req.IPs, net.ParseIP(ip))
+			}
+
+			cert, err := ca.Issue(dir, info.File, keyFile, req)
+			if err != nil {
+				return output.Wrap(output.CodeIO, err)
+			}
+			recordPair(dir, info.File, keyFile, cert)
+			rotated++
+		}
+
+		if rotated == 0 {
+			report.Warn("no leaf certificates found to rotate")
+		}
+		return writeCertEnv(root, dir)
+	},
+}
+
+var certsInspectCmd = &cobra.Command{
+	Use:   "inspect",
+	Short: "Show subjects, SANs, expiry and CA verification of the certificates",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, _, dir, err := certsTarget()
+		if err != nil {
+			return err
+		}
+		infos, err := certs.Inspect(dir, time.Now())
+		if err != nil {
+			return output.Wrap(output.CodeIO, err)
+		}
+		report.Data = infos
+		if len(infos) == 0 {
+			return output.Errorf(output.CodeNotFound, "no certificates in %s (run 'helix-cli certs init')", dir)
+		}
+
+		tw := tabwriter.NewWriter(humanOut(), 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "FILE\tUSAGE\tSUBJECT\tSANS\tEXPIRES\tDAYS LEFT\tVERIFIED")
+		for _, info := range infos {
+			verified := "-"
+			if !info.IsCA {
+				verified = fmt.Sprintf("%t", info.Verified)
+			}
+			sans := strings.Join(append(append([]string{}, info.DNSNames...), info.IPs...), ",")
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n", info.File, info.Usage, info.Subject, sans, info.NotAfter.Format("2006-01-02"), info.DaysLeft, verified)
+
+			switch {
+			case info.Error != "":
+				report.Warn(fmt.Sprintf("%s: %s", info.File, info.Error))
+			case info.DaysLeft < 30:
+				report.Warn(fmt.Sprintf("%s expires in %d days (run 'helix-cli certs rotate')", info.File, info.DaysLeft))
+			}
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		for _, w := range report.Warnings {
+			printf("WARNING: %s\n", w)
+		}
+		return nil
+	},
+}
+
+func init() {
+	certsCmd.PersistentFlags().StringVar(&certsDir, "dir", "certs", "Certificate directory, relative to the project root")
+	certsCmd.PersistentFlags().IntVar(&certsDays, "days", 365, "Validity of issued server/client certificates in days")
+	certsInitCmd.Flags().StringSliceVar(&certsSANs, "san", nil, "Extra DNS name or IP for the server certificate (repeatable)")
+	certsInitCmd.Flags().BoolVar(&certsForce, "force", false, "Replace an existing CA")
+	certsIssueCmd.Flags().StringSliceVar(&certsSANs, "san", nil, "Extra DNS name or IP for the server certificate (repeatable)")
+	certsRotateCmd.Flags().BoolVar(&certsRotateCA, "ca", false, "Also regenerate the CA")
+
+	certsCmd.AddCommand(certsInitCmd)
+	certsCmd.AddCommand(certsIssueCmd)
+	certsCmd.AddCommand(certsRotateCmd)
+	certsCmd.AddCommand(certsInspectCmd)
+}
+
+// certsTarget resolves the project root, the service name used for SANs and the certs directory.
+func certsTarget() (root, service, dir string, err error) {
+	root, err = os.Getwd()
+	if err != nil {
+		return "", "", "", err
+	}
+	service = filepath.Base(root)
+
+	project, err := manifest.FindProject(root)
+	switch {
+	case err == nil:
+		root, service = project.Root, project.Name
+	case !errors.Is(err, manifest.ErrNoProject):
+		return "", "", "", err
+	}
+
+	dir = certsDir
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(root, dir)
+	}
+	return root, service, dir, nil
+}
+
+func loadCA(dir string) (*certs.Authority, error) {
+	ca, err := certs.LoadCA(dir)
+	if errors.Is(err, certs.ErrNoCA) {
+		return nil, output.Wrap(output.CodeNotFound, err)
+	}
+	return ca, output.Wrap(output.CodeIO, err)
+}
+
+func leafValidity() time.Duration {
+	return time.Duration(certsDays) * 24 * time.Hour
+}
+
+func issueServer(ca *certs.Authority, dir, service, certFile, keyFile string) error {
+	dns, ips := certs.ServerHosts(service, certsSANs)
+	cert, err := ca.Issue(dir, certFile, keyFile, certs.Request{CommonName: service, DNSNames: dns, IPs: ips, Validity: leafValidity()})
+	if err != nil {
+		return output.Wrap(output.CodeIO, err)
+	}
+	recordPair(dir, certFile, keyFile, cert)
+	return nil
+}
+
+func issueClient(ca *certs.Authority, dir, name, certFile, keyFile string) error {
+	cert, err := ca.Issue(dir, certFile, keyFile, certs.Request{CommonName: name, Client: true, Validity: leafValidity()})
+	if err != nil {
+		return output.Wrap(output.CodeIO, err)
+	}
+	recordPair(dir, certFile, keyFile, cert)
+	return nil
+}
+
+func recordPair(dir, certFile, keyFile string, cert *x509.Certificate) {
+	sans := append(append([]string{}, cert.DNSNames...), ipStrings(cert)...)
+	printf("  %-12s CN=%s expires %s", certFile, cert.Subject.CommonName, cert.NotAfter.Format("2006-01-02"))
+	if len(sans) > 0 {
+		printf(" SANs=%s", strings.Join(sans, ","))
+	}
+	printLine()
+	report.Created(filepath.Join(dir, certFile))
+	report.Created(filepath.Join(dir, keyFile))
+}
+
+func ipStrings(cert *x509.Certificate) []string {
+	out := make([]string, 0, len(cert.IPAddresses))
+	for _, ip := range cert.IPAddresses {
+		out = append(out, ip.String())
+	}
+	return out
+}
+
+// writeCertEnv points the HTTP/gRPC mTLS settings in .env at the generated files.
+func writeCertEnv(root, dir string) error {
+	envPath := filepath.Join(root, ".env")
+	if _, err := os.Stat(envPath); os.IsNotExist(err) {
+		report.Warn("no .env found, mTLS paths were not written")
+		return nil
+	}
+
+	rel := relTo(root, dir)
+	values := map[string]string{}
+	for _, prefix := range []string{"HTTP", "GRPC"} {
+		values[prefix+"_MTLS_CA_CERT"] = rel + "/" + certs.CACert
+		values[prefix+"_MTLS_SERVER_CERT"] = rel + "/" + certs.ServerCert
+		values[prefix+"_MTLS_SERVER_KEY"] = rel + "/" + certs.ServerKey
+	}
+	if err := certs.UpdateEnvFile(envPath, values); err != nil {
+		return output.Errorf(output.CodeIO, "update .env: %w", err)
+	}
+	report.Modified(envPath)
+	return nil
+}
+
+func relTo(root, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(path)
+}
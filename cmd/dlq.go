@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/godamri/helix-cli/internal/dlq"
+	"github.com/godamri/helix-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// Connection flags shared by every dlq subcommand.
+var (
+	dlqURL    string
+	dlqToken  string
+	dlqBearer string
+	dlqDSN    string
+)
+
+// Filter flags.
+var (
+	dlqConsumer string
+	dlqTopic    string
+	dlqStatus   string
+	dlqError    string
+	dlqFrom     string
+	dlqTo       string
+	dlqLimit    int
+	dlqYes      bool
+)
+
+var dlqCmd = &cobra.Command{
+	Use:   "dlq",
+	Short: "Browse dead-lettered messages and redrive them to their source topic",
+	Long: `Operates on the dead_letters table of a service, which records every message
+its consumers failed on: the consumer, the source topic, the attempts and the
+last error. Works through the operator API (--url, needs ADMIN_API_ENABLED=true
+and --token / HELIX_ADMIN_TOKEN) or directly in Postgres (--dsn / HELIX_DLQ_DSN).
+
+Redrive queues DEAD or DISCARDED messages; the service's dlq worker mode
+(APP_MODE=dlq or all) publishes them back to their source topic at
+DLQ_REDRIVE_RATE messages per second. Discard marks DEAD or QUEUED messages
+DISCARDED so they are kept for DLQ_RETENTION but never redriven.`,
+	Example: `  helix-cli dlq list --url http://localhost:8080 --consumer OrderCreated --status DEAD
+  helix-cli dlq list --error "connection refused" --from 2024-05-01T00:00:00Z
+  helix-cli dlq show 6f1c2d3e-... --dsn "$DB_DSN"
+  helix-cli dlq redrive --consumer OrderCreated --error "product not found"
+  helix-cli dlq discard 6f1c2d3e-...`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var dlqListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "List dead letters with their failure, most recent first",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true, // Remote failures aren't usage errors
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := dlqFilter(nil)
+		if err != nil {
+			return err
+		}
+		f.Limit = dlqLimit
+		return withDLQ(func(ctx context.Context, c dlq.Client) error {
+			msgs, err := c.List(ctx, f)
+			if err != nil {
+				return output.Wrap(output.CodeRemote, err)
+			}
+			report.Data = msgs
+
+			tw := tabwriter.NewWriter(humanOut(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "ID\tSTATUS\tCONSUMER\tTOPIC\tKEY\tATTEMPTS\tREDRIVES\tFIRST FAILED\tLAST ERROR")
+			for _, m := range msgs {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\t%d\t%s\t%s\n", m.ID, m.Status, m.Consumer, m.Topic, m.Key,
+					m.Attempts, m.RedriveCount, m.FirstFailedAt.Format(time.RFC3339), truncate(m.LastError, 60))
+			}
+			return tw.Flush()
+		})
+	},
+}
+
+var dlqShowCmd = &cobra.Command{
+	Use:          "show <id>",
+	Short:        "Show one dead letter with its payload and failure metadata",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withDLQ(func(ctx context.Context, c dlq.Client) error {
+			m, err := c.Get(ctx, args[0])
+			if err != nil {
+				return output.Wrap(output.CodeRemote, err)
+			}
+			report.Data = m
+
+			b, _ := json.MarshalIndent(m, "", "  ")
+			printLine(string(b))
+			return nil
+		})
+	},
+}
+
+var dlqRedriveCmd = &cobra.Command{
+	Use:          "redrive [id...]",
+	Short:        "Queue dead letters for redrive by ID or by consumer, topic, error and time range",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := dlqBulkFilter(args, "Redrive")
+		if err != nil {
+			return err
+		}
+		if err := runDLQAction("Queued", f, dlq.Client.Redrive); err != nil {
+			return err
+		}
+		report.Next("the service's dlq worker (APP_MODE=dlq or all) publishes queued messages at DLQ_REDRIVE_RATE per second")
+		return nil
+	},
+}
+
+var dlqDiscardCmd = &cobra.Command{
+	Use:          "discard [id...]",
+	Short:        "Mark dead letters DISCARDED by ID or by consumer, topic, error and time range",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := dlqBulkFilter(args, "Discard")
+		if err != nil {
+			return err
+		}
+		return runDLQAction("Discarded", f, dlq.Client.Discard)
+	},
+}
+
+func init() {
+	pf := dlqCmd.PersistentFlags()
+	pf.StringVar(&dlqURL, "url", os.Getenv("HELIX_ADMIN_URL"), "Service base URL for the operator API (env HELIX_ADMIN_URL)")
+	pf.StringVar(&dlqToken, "token", os.Getenv("HELIX_ADMIN_TOKEN"), "Operator token, sent as X-Admin-Token (env HELIX_ADMIN_TOKEN)")
+	pf.StringVar(&dlqBearer, "bearer", os.Getenv("HELIX_BEARER_TOKEN"), "JWT for services running with AUTH_ENABLED (env HELIX_BEARER_TOKEN)")
+	pf.StringVar(&dlqDSN, "dsn", os.Getenv("HELIX_DLQ_DSN"), "Postgres DSN; bypasses the API (env HELIX_DLQ_DSN)")
+
+	for _, c := range []*cobra.Command{dlqListCmd, dlqRedriveCmd, dlqDiscardCmd} {
+		c.Flags().StringVar(&dlqConsumer, "consumer", "", "Only messages of this consumer (manifest name, e.g. OrderCreated)")
+		c.Flags().StringVar(&dlqTopic, "topic", "", "Only messages of this source topic")
+		c.Flags().StringVar(&dlqStatus, "status", "", "Only messages in this status ("+strings.Join(dlq.Statuses, ", ")+")")
+		c.Flags().StringVar(&dlqError, "error", "", "Only messages whose last error contains this text (case-insensitive)")
+		c.Flags().StringVar(&dlqFrom, "from", "", "Only messages that first failed at or after this time (RFC3339)")
+		c.Flags().StringVar(&dlqTo, "to", "", "Only messages that first failed before this time (RFC3339)")
+	}
+	dlqListCmd.Flags().IntVar(&dlqLimit, "limit", 100, "Maximum number of messages (max 1000)")
+	for _, c := range []*cobra.Command{dlqRedriveCmd, dlqDiscardCmd} {
+		c.Flags().BoolVarP(&dlqYes, "yes", "y", false, "Don't ask for confirmation of bulk actions")
+	}
+
+	dlqCmd.AddCommand(dlqListCmd)
+	dlqCmd.AddCommand(dlqShowCmd)
+	dlqCmd.AddCommand(dlqRedriveCmd)
+	dlqCmd.AddCommand(dlqDiscardCmd)
+}
+
+// withDLQ connects to the selected backend and runs fn.
+func withDLQ(fn func(ctx context.Context, c dlq.Client) error) error {
+	ctx := context.Background()
+
+	var c dlq.Client
+	switch {
+	case dlqDSN != "":
+		db, err := dlq.OpenDB(ctx, dlqDSN)
+		if err != nil {
+			return output.Errorf(output.CodeRemote, "connect to database: %w", err)
+		}
+		c = db
+	case dlqURL != "":
+		if dlqToken == "" {
+			return output.Errorf(output.CodeInvalidArgument, "--token (or HELIX_ADMIN_TOKEN) is required with --url")
+		}
+		c = dlq.NewAPIClient(dlqURL, dlqToken, dlqBearer)
+	default:
+		return output.Errorf(output.CodeInvalidArgument, "set --url (operator API) or --dsn (direct database access)")
+	}
+	defer c.Close()
+
+	return fn(ctx, c)
+}
+
+func runDLQAction(verb string, f dlq.Filter, action func(dlq.Client, context.Context, dlq.Filter) (int64, error)) error {
+	return withDLQ(func(ctx context.Context, c dlq.Client) error {
+		n, err := action(c, ctx, f)
+		if err != nil {
+			return output.Wrap(output.CodeRemote, err)
+		}
+		report.Data = map[string]int64{"affected": n}
+		report.Message(fmt.Sprintf("%s %d message(s)", verb, n))
+		printf("%s %d message(s).\n", verb, n)
+		return nil
+	})
+}
+
+// dlqBulkFilter builds the filter of a bulk action and confirms it unless it
+// only names IDs or --yes is set.
+func dlqBulkFilter(ids []string, action string) (dlq.Filter, error) {
+	f, err := dlqFilter(ids)
+	if err != nil {
+		return f, err
+	}
+	if f.Empty() {
+		return f, output.Errorf(output.CodeInvalidArgument, "%s needs IDs or at least one of --consumer, --topic, --status, --error, --from, --to", strings.ToLower(action))
+	}
+	if rest := f; len(ids) > 0 {
+		if rest.IDs = nil; rest.Empty() {
+			return f, nil
+		}
+	}
+	ok, err := askConfirm(dlqYes, &survey.Confirm{
+		Message: fmt.Sprintf("%s every message matching %s?", action, describeDLQFilter(f)),
+	})
+	if err != nil {
+		return f, err
+	}
+	if !ok {
+		return f, output.Errorf(output.CodeInvalidArgument, "%s not confirmed (pass --yes)", strings.ToLower(action))
+	}
+	return f, nil
+}
+
+func dlqFilter(ids []string) (dlq.Filter, error) {
+	f := dlq.Filter{IDs: ids, Consumer: dlqConsumer, Topic: dlqTopic, Status: strings.ToUpper(dlqStatus), Error: dlqError}
+	if f.Status != "" && !slices.Contains(dlq.Statuses, f.Status) {
+		return f, output.Errorf(output.CodeInvalidArgument, "invalid --status '%s' (expected one of %s)", dlqStatus, strings.Join(dlq.Statuses, ", "))
+	}
+	for _, t := range []struct {
+		flag, value string
+		dest        **time.Time
+	}{{"from", dlqFrom, &f.From}, {"to", dlqTo, &f.To}} {
+		if t.value == "" {
+			continue
+		}
+		v, err := time.Parse(time.RFC3339, t.value)
+		if err != nil {
+			return f, output.Errorf(output.CodeInvalidArgument, "invalid --%s '%s' (expected RFC3339, e.g. 2024-05-01T00:00:00Z)", t.flag, t.value)
+		}
+		*t.dest = &v
+	}
+	return f, nil
+}
+
+func describeDLQFilter(f dlq.Filter) string {
+	var parts []string
+	if len(f.IDs) > 0 {
+		parts = append(parts, fmt.Sprintf("%d id(s)", len(f.IDs)))
+	}
+	for _, kv := range [][2]string{{"consumer", f.Consumer}, {"topic", f.Topic}, {"status", f.Status}, {"error", f.Error}} {
+		if kv[1] != "" {
+			parts = append(parts, kv[0]+"="+kv[1])
+		}
+	}
+	if f.From != nil {
+		parts = append(parts, "from="+f.From.Format(time.RFC3339))
+	}
+	if f.To != nil {
+		parts = append(parts, "to="+f.To.Format(time.RFC3339))
+	}
+	return strings.Join(parts, " ")
+}
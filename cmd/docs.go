@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/godamri/helix-cli/internal/asyncapi"
+	"github.com/godamri/helix-cli/internal/events"
+	"github.com/godamri/helix-cli/internal/inspect"
+	"github.com/godamri/helix-cli/internal/manifest"
+	"github.com/godamri/helix-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// asyncAPIFile is embedded by docs/asyncapi.go and served at /asyncapi/doc.json.
+const asyncAPIFile = "docs/asyncapi.json"
+
+var asyncAPIVersion string
+
+var docsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Generate API documentation that swag does not cover",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var docsAsyncAPICmd = &cobra.Command{
+	Use:   "asyncapi",
+	Short: "Generate the AsyncAPI 3 document of the service's topics",
+	Long: `Writes docs/asyncapi.json from the service source:
+  - published topics: the event contracts in internal/core/event and the publishEvent sites,
+  - consumed topics: the declared consumers (consumers_gen.go) and any other
+    messaging.ConsumerConfig literals, with their DLQs,
+  - payloads: the JSON Schemas of the contracts (see 'helix-cli events').
+
+The service embeds the file and serves it at /asyncapi/doc.json next to
+/swagger/* when DOCS_ENABLED is set. init and 'new entity' regenerate it.`,
+	Example: `  helix-cli docs asyncapi
+  helix-cli docs asyncapi --version 1.4.0`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root := "."
+		project, err := manifest.FindProject(".")
+		switch {
+		case err == nil:
+			root = project.Root
+		case errors.Is(err, manifest.ErrNoProject):
+			project = nil
+		default:
+			return err
+		}
+
+		doc, warnings, err := writeAsyncAPI(root, project, asyncAPIVersion)
+		if err != nil {
+			return err
+		}
+		for _, w := range warnings {
+			report.Warn(w)
+		}
+		report.Data = doc
+		printf("%s up to date: %d channel(s), %d operation(s), %d message(s)\n",
+			asyncAPIFile, len(doc.Channels), len(doc.Operations), len(doc.Components.Messages))
+		return nil
+	},
+}
+
+func init() {
+	docsAsyncAPICmd.Flags().StringVar(&asyncAPIVersion, "version", "", "info.version of the document (default: latest git tag, else 0.0.0)")
+
+	docsCmd.AddCommand(docsAsyncAPICmd)
+}
+
+// writeAsyncAPI builds the document of the service at root and writes it when it
+// changed. It also returns the warnings of inspecting the source.
+func writeAsyncAPI(root string, project *manifest.Project, version string) (*asyncapi.Document, []string, error) {
+	r, err := inspect.Inspect(root, project)
+	if err != nil {
+		return nil, nil, output.Wrap(output.CodeIO, err)
+	}
+	contracts, err := events.Load(root)
+	if err != nil {
+		return nil, nil, output.Wrap(output.CodeInvalidArgument, err)
+	}
+
+	if version == "" {
+		version = "0.0.0"
+		if tag, err := events.LatestTag(root); err == nil && tag != "" {
+			version = strings.TrimPrefix(tag, "v")
+		}
+	}
+	opts := asyncapi.Options{Version: version}
+	if project != nil {
+		opts.Broker = project.Broker
+		if opts.Broker == "" {
+			opts.Broker = "kafka" // Projects from before --broker
+		}
+	}
+
+	doc := asyncapi.Build(r, contracts, opts)
+	b, err := doc.Render()
+	if err != nil {
+		return nil, nil, output.Wrap(output.CodeInternal, err)
+	}
+
+	file := filepath.Join(root, filepath.FromSlash(asyncAPIFile))
+	current, readErr := os.ReadFile(file)
+	if readErr == nil && bytes.Equal(current, b) {
+		return doc, r.Warnings, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return nil, nil, output.Wrap(output.CodeIO, err)
+	}
+	if err := os.WriteFile(file, b, 0644); err != nil {
+		return nil, nil, output.Wrap(output.CodeIO, err)
+	}
+	if readErr == nil {
+		report.Modified(file)
+	} else {
+		report.Created(file)
+	}
+	return doc, r.Warnings, nil
+}
+
+// refreshAsyncAPI regenerates docs/asyncapi.json after scaffolding. The service
+// does not build without the file, so a failure is worth a warning.
+func refreshAsyncAPI(root string) {
+	project, err := manifest.FindProject(root)
+	if err != nil {
+		project = nil
+	}
+	// Right after scaffolding the modules may not be downloaded yet; the source
+	// walk covers that, so inspection warnings are not worth reporting here.
+	if _, _, err := writeAsyncAPI(root, project, ""); err != nil {
+		report.Warn(fmt.Sprintf("%s not generated; the build needs it (run 'helix-cli docs asyncapi'): %v", asyncAPIFile, err))
+	}
+}
@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/godamri/helix-cli/internal/events"
+	"github.com/godamri/helix-cli/internal/manifest"
+	"github.com/godamri/helix-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var eventsAgainst string
+
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Generate and check the JSON Schemas of a service's event contracts",
+	Long: `Event contracts are the structs in internal/core/event: each has a Topic() and a
+SchemaVersion(). Their JSON Schemas live in api/events/<topic>/v<version>.json and
+are checked into the repository, so released versions can be compared.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var eventsGenerateCmd = &cobra.Command{
+	Use:          "generate",
+	Short:        "Write api/events from the contracts in internal/core/event",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, contracts, err := loadContracts()
+		if err != nil {
+			return err
+		}
+
+		written, err := writeEventSchemas(root, contracts)
+		if err != nil {
+			return err
+		}
+
+		// Schemas of released versions stay: removing a contract is a breaking
+		// change that 'events check' reports, not something to hide here.
+		for _, file := range schemaFiles(root) {
+			if !written[file] {
+				report.Warn("No contract for " + relTo(root, file) + "; 'events check' will flag it once released")
+			}
+		}
+
+		report.Data = map[string]interface{}{"dir": events.SchemaDir, "schemas": len(contracts)}
+		printf("%d event schema(s) up to date in %s.\n", len(contracts), events.SchemaDir)
+		report.Next("Commit " + events.SchemaDir + " with the contract changes")
+		return nil
+	},
+}
+
+var eventsCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Fail on stale schemas or backward-incompatible contract changes",
+	Long: `Regenerates the schemas in memory and fails when:
+  - a file in api/events is missing or differs from its contract (run 'events generate'),
+  - a schema released at --against changed incompatibly, or its contract is gone.
+
+Compatible changes are those every existing consumer still accepts: new
+properties, or a type narrowed (e.g. number to integer). Removing a required
+property, making one optional, or changing a type or format needs a new struct
+with SchemaVersion()+1 instead. --against defaults to the latest git tag, or
+HEAD when the repository has no tags.`,
+	Example: `  helix-cli events check
+  helix-cli events check --against v1.4.0
+  helix-cli events check --against origin/main --json`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true, // A failed check isn't a usage error
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, contracts, err := loadContracts()
+		if err != nil {
+			return err
+		}
+
+		type finding struct {
+			File   string `json:"file"`
+			Topic  string `json:"topic,omitempty"`
+			Reason string `json:"reason"`
+		}
+		var stale, breaking []finding
+
+		current := map[string]events.Contract{}
+		for _, c := range contracts {
+			current[c.Path()] = c
+			b, err := c.Render()
+			if err != nil {
+				return output.Wrap(output.CodeInternal, err)
+			}
+			onDisk, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(c.Path())))
+			switch {
+			case err != nil:
+				stale = append(stale, finding{File: c.Path(), Topic: c.Topic, Reason: "missing"})
+			case !bytes.Equal(onDisk, b):
+				stale = append(stale, finding{File: c.Path(), Topic: c.Topic, Reason: "out of date"})
+			}
+		}
+
+		ref := eventsAgainst
+		if ref == "" {
+			tag, err := events.LatestTag(root)
+			switch {
+			case errors.Is(err, events.ErrNoHistory):
+				report.Warn("No git history, so nothing is released yet; only checked that " + events.SchemaDir + " is up to date")
+			case err != nil:
+				return output.Wrap(output.CodeCommandFailed, err)
+			case tag != "":
+				ref = tag
+			default:
+				ref = "HEAD"
+			}
+		}
+		released := map[string][]byte{}
+		if ref != "" {
+			if released, err = events.Released(root, ref); err != nil {
+				return output.Wrap(output.CodeCommandFailed, err)
+			}
+		}
+
+		files := make([]string, 0, len(released))
+		for file := range released {
+			files = append(files, file)
+		}
+		sort.Strings(files)
+		for _, file := range files {
+			old, err := events.Parse(released[file])
+			if err != nil {
+				return output.Errorf(output.CodeInvalidArgument, "%s at %s: %v", file, ref, err)
+			}
+			c, ok := current[file]
+			if !ok {
+				breaking = append(breaking, finding{File: file, Reason: "released schema has no contract anymore"})
+				continue
+			}
+			for _, change := range events.Compare(old, c.Schema) {
+				breaking = append(breaking, finding{File: file, Topic: c.Topic, Reason: change.String()})
+			}
+		}
+
+		report.Data = map[string]interface{}{
+			"ok":        len(stale) == 0 && len(breaking) == 0,
+			"against":   ref,
+			"contracts": len(contracts),
+			"released":  len(released),
+			"stale":     stale,
+			"breaking":  breaking,
+		}
+
+		for _, f := range append(stale, breaking...) {
+			printf("%s: %s\n", f.File, f.Reason)
+		}
+
+		switch {
+		case len(breaking) > 0:
+			report.Next("Keep the released struct and add a new one with SchemaVersion()+1 for the changed payload")
+			return output.Errorf(output.CodeIncompatible, "%d backward-incompatible change(s) against %s", len(breaking), ref)
+		case len(stale) > 0:
+			report.Next("Run 'helix-cli events generate' and commit " + events.SchemaDir)
+			return output.Errorf(output.CodeIncompatible, "%d event schema(s) out of date", len(stale))
+		}
+		if ref != "" {
+			printf("%d event contract(s) compatible with %s.\n", len(contracts), ref)
+		} else {
+			printf("%d event schema(s) up to date.\n", len(contracts))
+		}
+		return nil
+	},
+}
+
+func init() {
+	eventsCheckCmd.Flags().StringVar(&eventsAgainst, "against", "", "Git ref of the released schemas (default: latest tag, else HEAD)")
+
+	eventsCmd.AddCommand(eventsGenerateCmd)
+	eventsCmd.AddCommand(eventsCheckCmd)
+}
+
+// loadContracts finds the project around the working directory and parses its contracts.
+func loadContracts() (string, []events.Contract, error) {
+	root := "."
+	project, err := manifest.FindProject(".")
+	switch {
+	case err == nil:
+		root = project.Root
+	case !errors.Is(err, manifest.ErrNoProject):
+		return "", nil, err
+	}
+
+	contracts, err := events.Load(root)
+	if err != nil {
+		return "", nil, output.Wrap(output.CodeInvalidArgument, err)
+	}
+	if len(contracts) == 0 {
+		return "", nil, output.Errorf(output.CodeNotFound, "no event contracts in %s (structs with Topic() and SchemaVersion())", events.Dir)
+	}
+	return root, contracts, nil
+}
+
+// schemaFiles lists the schema files under api/events.
+func schemaFiles(root string) []string {
+	var files []string
+	filepath.WalkDir(filepath.Join(root, events.SchemaDir), func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && strings.HasSuffix(path, ".json") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files
+}
+
+// writeEventSchemas renders the schema of each contract, writing only the
+// files that changed, and returns the paths it owns.
+func writeEventSchemas(root string, contracts []events.Contract) (map[string]bool, error) {
+	written := map[string]bool{}
+	for _, c := range contracts {
+		b, err := c.Render()
+		if err != nil {
+			return nil, output.Wrap(output.CodeInternal, err)
+		}
+		file := filepath.Join(root, filepath.FromSlash(c.Path()))
+		written[file] = true
+
+		current, readErr := os.ReadFile(file)
+		if readErr == nil && bytes.Equal(current, b) {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return nil, output.Wrap(output.CodeIO, err)
+		}
+		if err := os.WriteFile(file, b, 0644); err != nil {
+			return nil, output.Wrap(output.CodeIO, err)
+		}
+		if readErr == nil {
+			report.Modified(file)
+		} else {
+			report.Created(file)
+		}
+	}
+	return written, nil
+}
+
+// refreshEventSchemas regenerates api/events after scaffolding added contracts.
+// Failures only warn: the service is usable and 'events generate' can be rerun.
+func refreshEventSchemas(root string) {
+	contracts, err := events.Load(root)
+	if err == nil {
+		_, err = writeEventSchemas(root, contracts)
+	}
+	if err != nil {
+		report.Warn(fmt.Sprintf("event schemas not generated (run 'helix-cli events generate'): %v", err))
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"math/rand"
@@ -12,6 +13,10 @@ import (
 	"unicode"
 
 	"github.com/AlecAivazis/survey/v2"
+	"github.com/godamri/helix-cli/internal/hooks"
+	"github.com/godamri/helix-cli/internal/manifest"
+	"github.com/godamri/helix-cli/internal/output"
+	"github.com/godamri/helix-cli/internal/scaffold"
 	helixTemplate "github.com/godamri/helix-cli/internal/template"
 	"github.com/spf13/cobra"
 )
@@ -39,29 +44,39 @@ func kebabToCamel(s string) string {
 	return string(r)
 }
 
+// Flags replacing the interactive prompts (required with --output json).
+var (
+	initDriver string
+	initBroker string
+	initYes    bool
+)
+
+// brokers are the outbox producer backends registered in the generated service.
+var brokers = []string{"kafka", "nats", "rabbitmq", "redis", "webhook"}
+
 var initCmd = &cobra.Command{
-	Use:   "init [name]",
-	Short: "Initialize a new Helix microservice project (Enterprise Grade)",
+	Use:     "init [name]",
+	Short:   "Initialize a new Helix microservice project (Enterprise Grade)",
+	Example: "  helix-cli init svc-order --driver pgx --yes --output json\n  helix-cli init svc-order --driver ent --broker nats",
+	Args:    cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+		logger := cliLogger()
 
 		var projectName string
-		if len(args) < 1 {
-			prompt := &survey.Input{Message: "What is the project name? (e.g. svc-order)"}
-			if err := survey.AskOne(prompt, &projectName); err != nil {
-				return err
-			}
-		} else {
+		if len(args) > 0 {
 			projectName = args[0]
 		}
+		prompt := &survey.Input{Message: "What is the project name? (e.g. svc-order)"}
+		if err := askInput(&projectName, "project name argument", prompt); err != nil {
+			return err
+		}
 
 		if !strings.HasPrefix(projectName, "svc-") {
-			var confirm bool
-			prompt := &survey.Confirm{
+			confirm, err := askConfirm(initYes, &survey.Confirm{
 				Message: fmt.Sprintf("Project name '%s' doesn't start with 'svc-'. Auto-fix to 'svc-%s'?", projectName, projectName),
 				Default: true,
-			}
-			if err := survey.AskOne(prompt, &confirm); err != nil {
+			})
+			if err != nil {
 				return err
 			}
 			if confirm {
@@ -72,32 +87,71 @@ var initCmd = &cobra.Command{
 		// --- PREVENT RESERVED NAMES ---
 		rawEntityName := strings.TrimPrefix(projectName, "svc-")
 		forbidden := map[string]bool{
-			"ent":      true,
-			"entity":   true,
-			"internal": true,
-			"pkg":      true,
-			"app":      true,
-			"go":       true,
+			"ent":         true,
+			"entity":      true,
+			"internal":    true,
+			"pkg":         true,
+			"app":         true,
+			"inbox":       true, // ent/schema/inbox.go, outbox.go and dead_letter.go are always generated
+			"outbox":      true,
+			"dead-letter": true,
+			"saga":        true, // ent/schema/saga.go comes with the first 'new saga'
+			"job-run":     true, // and job_run.go with the first 'new job'
+			"go":          true,
 		}
 		if forbidden[rawEntityName] {
-			return fmt.Errorf("FATAL: '%s' is a reserved keyword or framework name. Naming your entity '%s' will break code generation. Please use a real domain name (e.g. svc-user, svc-order).", rawEntityName, kebabToPascal(rawEntityName))
+			return output.Errorf(output.CodeInvalidArgument, "FATAL: '%s' is a reserved keyword or framework name. Naming your entity '%s' will break code generation. Please use a real domain name (e.g. svc-user, svc-order).", rawEntityName, kebabToPascal(rawEntityName))
 		}
 
 		// --- Driver Selection ---
-		var driver string
+		driver := initDriver
 		promptDriver := &survey.Select{
 			Message: "Choose Database Driver Strategy:",
 			Options: []string{"ent", "pgx"},
 			Default: "ent",
 			Help:    "Ent: Type-safe ORM (Productivity). PGX: Raw SQL (Performance/Control).",
 		}
-		if err := survey.AskOne(promptDriver, &driver); err != nil {
+		if err := askSelect(&driver, "driver", promptDriver); err != nil {
+			return err
+		}
+
+		broker := initBroker
+		if broker == "" && (initYes || jsonOutput()) {
+			broker = "kafka" // Scripts written before --broker existed keep working
+		}
+		promptBroker := &survey.Select{
+			Message: "Choose Event Broker for the outbox:",
+			Options: brokers,
+			Default: "kafka",
+			Help:    "Backend the outbox worker publishes to. Consumers stay on Kafka.",
+		}
+		if err := askSelect(&broker, "broker", promptBroker); err != nil {
 			return err
 		}
 
 		destinationDir := fmt.Sprintf("./%s", projectName)
 		if _, err := os.Stat(destinationDir); err == nil {
-			return fmt.Errorf("directory '%s' already exists", destinationDir)
+			return output.Errorf(output.CodeAlreadyExists, "directory '%s' already exists", destinationDir)
+		}
+
+		// Inside a workspace the service reuses the root infra compose file and go.work.
+		infraComposeFile := "docker-compose.infra.yml"
+		sharedProtoDir := ""
+		ws, err := manifest.FindWorkspace(".")
+		if err != nil && !errors.Is(err, manifest.ErrNoWorkspace) {
+			return err
+		}
+		if ws != nil {
+			absDest, _ := filepath.Abs(destinationDir)
+			rel, err := filepath.Rel(absDest, filepath.Join(ws.Root, "docker-compose.infra.yml"))
+			if err != nil {
+				return fmt.Errorf("resolve workspace infra path: %w", err)
+			}
+			infraComposeFile = filepath.ToSlash(rel)
+			if rel, err := filepath.Rel(absDest, filepath.Join(ws.Root, "api", "proto")); err == nil {
+				sharedProtoDir = filepath.ToSlash(rel)
+			}
+			slog.Info("Workspace detected, service will be registered", "workspace", ws.Name, "root", ws.Root)
 		}
 
 		r := rand.New(rand.NewSource(time.Now().UnixNano()))
@@ -121,67 +175,43 @@ var initCmd = &cobra.Command{
 			EntityNameLower:   entityNameLower,
 			EntityPluralLower: entityPluralNameLower,
 			Driver:            driver, // Pass driver choice
+			Broker:            broker,
+			InfraComposeFile:  infraComposeFile,
+			SharedProtoDir:    sharedProtoDir,
 		}
 
-		if TemplateFS == nil {
-			return fmt.Errorf("embedded template FS is nil")
+		fetcher, err := templateFetcher()
+		if err != nil {
+			return err
 		}
-		fetcher := helixTemplate.NewSmartFetcher(TemplateFS, logger)
 
 		generator := helixTemplate.NewGenerator(data, fetcher)
 
-		templateFiles := map[string]string{
-			"templates/Makefile":                                         filepath.Join(destinationDir, "Makefile"),
-			"templates/Dockerfile.tmpl":                                  filepath.Join(destinationDir, "Dockerfile"),
-			"templates/.air.toml":                                        filepath.Join(destinationDir, ".air.toml"),
-			"templates/docker-compose.yml":                               filepath.Join(destinationDir, "docker-compose.yml"),
-			"templates/docker-compose.infra.yml":                         filepath.Join(destinationDir, "docker-compose.infra.yml"),
-			"templates/.env":                                             filepath.Join(destinationDir, ".env"),
-			"templates/.golangci.yml":                                    filepath.Join(destinationDir, ".golangci.yml"),
-			"templates/buf.gen.yaml":                                     filepath.Join(destinationDir, "buf.gen.yaml"),
-			"templates/api/proto/v1/service.proto":                       filepath.Join(destinationDir, "api", "proto", "v1", fmt.Sprintf("%s.proto", entityFileName)),
-			"templates/app/cmd/server/main.go.tmpl":                      filepath.Join(destinationDir, "cmd", "server", "main.go"),
-			"templates/app/go.mod.tmpl":                                  filepath.Join(destinationDir, "go.mod"),
-			"templates/app/internal/pkg/config/config.go.tmpl":           filepath.Join(destinationDir, "internal", "pkg", "config", "config.go"),
-			"templates/app/scripts/gen-certs.sh":                         filepath.Join(destinationDir, "scripts", "gen-certs.sh"),
-			"templates/app/ent/runtime.go.tmpl":                          filepath.Join(destinationDir, "ent", "runtime.go"),
-			"templates/app/ent/entc.go.tmpl":                             filepath.Join(destinationDir, "ent", "entc.go"),
-			"templates/app/ent/generate.go.tmpl":                         filepath.Join(destinationDir, "ent", "generate.go"),
-			"templates/entity/ent_schema.go.tmpl":                        filepath.Join(destinationDir, "ent", "schema", fmt.Sprintf("%s.go", entityFileName)),
-			"templates/app/ent/schema/outbox.go.tmpl":                    filepath.Join(destinationDir, "ent", "schema", "outbox.go"),
-			"templates/entity/port_service.go.tmpl":                      filepath.Join(destinationDir, "internal", "core", "port", fmt.Sprintf("%s_service.go", entityFileName)),
-			"templates/entity/port_repository.go.tmpl":                   filepath.Join(destinationDir, "internal", "core", "port", fmt.Sprintf("%s_repository.go", entityFileName)),
-			"templates/app/internal/core/port/transaction.go.tmpl":       filepath.Join(destinationDir, "internal", "core", "port", "transaction.go"),
-			"templates/app/internal/core/port/outbox_repository.go.tmpl": filepath.Join(destinationDir, "internal", "core", "port", "outbox_repository.go"),
-			"templates/entity/entity.go.tmpl":                            filepath.Join(destinationDir, "internal", "core", "entity", fmt.Sprintf("%s.go", entityFileName)),
-
-			// DTO -> V1
-			"templates/entity/dto.go.tmpl": filepath.Join(destinationDir, "internal", "core", "dto", "v1", fmt.Sprintf("%s.go", entityFileName)),
-
-			"templates/entity/service_impl.go.tmpl":                               filepath.Join(destinationDir, "internal", "core", "service", fmt.Sprintf("%s_service.go", entityFileName)),
-			"templates/entity/repo_impl.go.tmpl":                                  filepath.Join(destinationDir, "internal", "adapter", "repository", fmt.Sprintf("%s_repository.go", entityFileName)),
-			"templates/app/internal/adapter/repository/transaction.go.tmpl":       filepath.Join(destinationDir, "internal", "adapter", "repository", "transaction.go"),
-			"templates/app/internal/adapter/repository/outbox_repository.go.tmpl": filepath.Join(destinationDir, "internal", "adapter", "repository", "outbox_repository.go"),
-
-			// Handlers -> V1
-			"templates/entity/handler_impl.go.tmpl":      filepath.Join(destinationDir, "internal", "adapter", "handler", "v1", fmt.Sprintf("%s_handler.go", entityFileName)),
-			"templates/entity/grpc_handler_impl.go.tmpl": filepath.Join(destinationDir, "internal", "adapter", "handler", "v1", fmt.Sprintf("%s_grpc_handler.go", entityFileName)),
-
-			"templates/app/internal/core/entity/errors.go.tmpl":              filepath.Join(destinationDir, "internal", "core", "entity", "errors.go"),
-			"templates/app/internal/adapter/worker/outbox.go.tmpl":           filepath.Join(destinationDir, "internal", "adapter", "worker", "outbox.go"),
-			"templates/app/internal/adapter/worker/consumer_handler.go.tmpl": filepath.Join(destinationDir, "internal", "adapter", "worker", fmt.Sprintf("consumer_%s.go", entityFileName)), // Added Consumer Handler
-
-			"templates/app/internal/adapter/handler/validation.go.tmpl": filepath.Join(destinationDir, "internal", "adapter", "handler", "v1", "validation.go"),
-
-			"templates/app/internal/pkg/middleware/deprecation.go.tmpl": filepath.Join(destinationDir, "internal", "pkg", "middleware", "deprecation.go"),
-
-			"templates/app/tests/integration/setup_test.go.tmpl": filepath.Join(destinationDir, "tests", "integration", "setup_test.go"),
-			"templates/app/migrations/.keep":                     filepath.Join(destinationDir, "migrations", ".keep"),
-			"templates/.github/workflows/ci.yml":                 filepath.Join(destinationDir, ".github", "workflows", "ci.yml"),
-
-			"templates/app/internal/core/port/database.go.tmpl":   filepath.Join(destinationDir, "internal", "core", "port", "database.go"),
-			"templates/app/internal/pkg/telemetry/logger.go.tmpl": filepath.Join(destinationDir, "internal", "pkg", "telemetry", "logger.go"),
-			"templates/app/internal/pkg/middleware/audit.go.tmpl": filepath.Join(destinationDir, "internal", "pkg", "middleware", "audit.go"),
+		templateFiles := scaffold.ServiceFiles(destinationDir, entityFileName)
+
+		if ws != nil {
+			// One shared copy lives at the workspace root.
+			delete(templateFiles, "templates/docker-compose.infra.yml")
+		}
+
+		pack, err := manifest.LoadPack(fetcher)
+		if err != nil {
+			return err
+		}
+		hookRunner := newHookRunner(pack, nil)
+
+		planned := make([]string, 0, len(templateFiles))
+		for _, destinationPath := range templateFiles {
+			planned = append(planned, destinationPath)
+		}
+		absDest, err := filepath.Abs(destinationDir)
+		if err != nil {
+			return err
+		}
+		hookCtx := hooks.Context{Command: "init", Root: absDest, Files: hooks.RelativeFiles(destinationDir, planned)}
+
+		if err := hookRunner.Run(manifest.PhasePreRender, hookCtx); err != nil {
+			return err
 		}
 
 		slog.Info("Starting scaffolding with SmartFetcher...", "driver", driver)
@@ -190,53 +220,130 @@ var initCmd = &cobra.Command{
 			if strings.HasSuffix(filepath.Base(sourcePath), ".keep") {
 				os.MkdirAll(filepath.Dir(destinationPath), 0755)
 				os.WriteFile(destinationPath, []byte{}, 0644)
+				report.Created(destinationPath)
 				continue
 			}
 
 			if err := generator.ProcessFile(sourcePath, destinationPath); err != nil {
 				os.RemoveAll(destinationDir)
-				return fmt.Errorf("TEMPLATE ERROR: %w", err)
+				return output.Errorf(output.CodeTemplate, "TEMPLATE ERROR: %w", err)
 			}
+			report.Created(destinationPath)
 		}
 
+		project := &manifest.Project{
+			Name:     projectName,
+			Module:   goModuleName,
+			Driver:   driver,
+			Broker:   broker,
+			Entities: []manifest.Entity{{Name: entityNameTitle, Driver: driver}},
+			Root:     absDest,
+		}
+		recordSources(project, fetcher, templateFiles)
+
+		// The service consumes its own creation events as a working example.
+		defaultConsumer := manifest.Consumer{
+			Name:           entityNameTitle + "Created",
+			Topic:          entityNameLower + ".created",
+			Event:          entityNameTitle + "Created",
+			MaxRetries:     3,
+			InitialBackoff: "1s",
+			MaxBackoff:     "5s",
+			DLQ:            entityNameLower + ".created.dlq",
+			Concurrency:    1,
+		}
+		consumerFile := filepath.Join(absDest, "internal", "adapter", "worker", fmt.Sprintf("consumer_%s_created.go", entityFileName))
+		if err := addConsumer(project, fetcher, defaultConsumer, consumerFile); err != nil {
+			os.RemoveAll(destinationDir)
+			return err
+		}
+		if err := project.Save(); err != nil {
+			os.RemoveAll(destinationDir)
+			return output.Errorf(output.CodeIO, "write project manifest: %w", err)
+		}
+		report.Created(filepath.Join(destinationDir, manifest.ProjectFile))
+		// From here on the project manifest exists, so its hooks run too.
+		hookRunner = newHookRunner(pack, project)
+
 		docsDir := filepath.Join(destinationDir, "docs")
 		os.MkdirAll(docsDir, 0755)
 		os.WriteFile(filepath.Join(docsDir, "docs.go"), []byte("package docs\n"), 0644)
+		report.Created(filepath.Join(docsDir, "docs.go"))
+
+		refreshEventSchemas(destinationDir)
+		refreshAsyncAPI(destinationDir)
+
+		if err := hookRunner.Run(manifest.PhasePostRender, hookCtx); err != nil {
+			// go mod and ent generation haven't run yet; don't leave a half-built service behind.
+			os.RemoveAll(destinationDir)
+			return output.Errorf(output.CodeHookFailed, "%w (removed the partially generated %s)", err, destinationDir)
+		}
 
 		logger.Info("Running go mod operations...")
-		runShellCommand(destinationDir, "go", "mod", "download")
-		runShellCommand(destinationDir, "go", "get", "github.com/kelseyhightower/envconfig")
-		runShellCommand(destinationDir, "go", "get", "github.com/go-playground/validator/v10")
-		runShellCommand(destinationDir, "go", "get", "github.com/prometheus/client_golang/prometheus/promhttp")
-		runShellCommand(destinationDir, "go", "get", "github.com/redis/go-redis/v9")
-		runShellCommand(destinationDir, "go", "get", "go.opentelemetry.io/otel")
-		runShellCommand(destinationDir, "go", "get", "entgo.io/contrib/entoas")
-		runShellCommand(destinationDir, "go", "get", "github.com/ogen-go/ogen")
-		runShellCommand(destinationDir, "go", "get", "github.com/testcontainers/testcontainers-go")
-		runShellCommand(destinationDir, "go", "get", "github.com/testcontainers/testcontainers-go/modules/postgres")
-		runShellCommand(destinationDir, "go", "get", "google.golang.org/grpc")
-		runShellCommand(destinationDir, "go", "get", "github.com/swaggo/http-swagger")
+		runOptionalCommand(destinationDir, "go", "mod", "download")
+		runOptionalCommand(destinationDir, "go", "get", "github.com/kelseyhightower/envconfig")
+		runOptionalCommand(destinationDir, "go", "get", "github.com/go-playground/validator/v10")
+		runOptionalCommand(destinationDir, "go", "get", "github.com/prometheus/client_golang/prometheus/promhttp")
+		runOptionalCommand(destinationDir, "go", "get", "github.com/redis/go-redis/v9")
+		runOptionalCommand(destinationDir, "go", "get", "go.opentelemetry.io/otel")
+		runOptionalCommand(destinationDir, "go", "get", "entgo.io/contrib/entoas")
+		runOptionalCommand(destinationDir, "go", "get", "github.com/ogen-go/ogen")
+		runOptionalCommand(destinationDir, "go", "get", "github.com/testcontainers/testcontainers-go")
+		runOptionalCommand(destinationDir, "go", "get", "github.com/testcontainers/testcontainers-go/modules/postgres")
+		runOptionalCommand(destinationDir, "go", "get", "google.golang.org/grpc")
+		runOptionalCommand(destinationDir, "go", "get", "github.com/swaggo/http-swagger")
+		runOptionalCommand(destinationDir, "go", "get", "github.com/nats-io/nats.go")
+		runOptionalCommand(destinationDir, "go", "get", "github.com/rabbitmq/amqp091-go")
 
 		logger.Info("Generating Ent code (Required for schema migration in both modes)...")
-		runShellCommand(destinationDir, "go", "generate", "./ent/...")
+		runOptionalCommand(destinationDir, "go", "generate", "./ent/...")
 		// We prepare the artifacts so user can run 'make init'
 
 		logger.Info("Finalizing modules...")
-		runShellCommand(destinationDir, "go", "mod", "tidy")
-		runShellCommand(destinationDir, "git", "init", "-b", "main")
+		runOptionalCommand(destinationDir, "go", "mod", "tidy")
 
-		fmt.Printf("\nProject %s Initialized Successfully using %s driver!\n", projectName, strings.ToUpper(driver))
+		if ws != nil {
+			if err := registerWorkspaceService(ws, destinationDir, data); err != nil {
+				return err
+			}
+		} else {
+			runOptionalCommand(destinationDir, "git", "init", "-b", "main")
+		}
+
+		if err := hookRunner.Run(manifest.PhasePostWire, hookCtx); err != nil {
+			report.Warn(fmt.Sprintf("%s is generated and wired, but the %s hooks after the failed one did not run", destinationDir, manifest.PhasePostWire))
+			return output.Errorf(output.CodeHookFailed, "%w (the project in %s is complete: fix the hook and run it there, or delete the directory)", err, destinationDir)
+		}
+
+		printf("\nProject %s Initialized Successfully using %s driver!\n", projectName, strings.ToUpper(driver))
+		report.Message(fmt.Sprintf("Project %s initialized using %s driver", projectName, driver))
+		report.Next(fmt.Sprintf("cd %s", projectName))
+		report.Next("make init")
 		return nil
 	},
 }
 
+func init() {
+	initCmd.Flags().StringVar(&initDriver, "driver", "", "Database driver: ent | pgx (skips the prompt)")
+	initCmd.Flags().StringVar(&initBroker, "broker", "", "Outbox event broker: kafka | nats | rabbitmq | redis | webhook (skips the prompt)")
+	initCmd.Flags().BoolVarP(&initYes, "yes", "y", false, "Accept defaults for confirmation prompts")
+}
+
 func runShellCommand(dir string, name string, args ...string) error {
 	cmd := exec.Command(name, args...)
 	cmd.Dir = dir
 	var stderr strings.Builder
 	cmd.Stderr = &stderr
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("cmd '%s %s' failed:\n%s", name, strings.Join(args, " "), stderr.String())
+		return output.Errorf(output.CodeCommandFailed, "cmd '%s %s' failed:\n%s", name, strings.Join(args, " "), stderr.String())
 	}
 	return nil
 }
+
+// runOptionalCommand runs a best-effort step; a failure becomes a warning instead of aborting.
+func runOptionalCommand(dir string, name string, args ...string) {
+	if err := runShellCommand(dir, name, args...); err != nil {
+		slog.Warn("Command failed", "cmd", name+" "+strings.Join(args, " "), "error", strings.TrimSpace(err.Error()))
+		report.Warn(err.Error())
+	}
+}
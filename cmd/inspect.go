@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/godamri/helix-cli/internal/inspect"
+	"github.com/godamri/helix-cli/internal/manifest"
+	"github.com/godamri/helix-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var inspectFormat string
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect [dir]",
+	Short: "Report a service's entities, routes, RPCs, topics and config",
+	Long: `Analyzes the Go source (go/packages) and proto files of a generated service and reports:
+entities and fields, chi routes, gRPC RPCs, outbox topics (publishEvent),
+consumer topics and DLQs (messaging.ConsumerConfig) and every envconfig variable.`,
+	Example: `  helix-cli inspect
+  helix-cli inspect ./svc-order --format mermaid > deps.mmd
+  helix-cli inspect --format json | jq '.routes[].path'`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := "."
+		if len(args) > 0 {
+			dir = args[0]
+		}
+
+		root := dir
+		project, err := manifest.FindProject(dir)
+		switch {
+		case err == nil:
+			root = project.Root
+		case errors.Is(err, manifest.ErrNoProject):
+			if _, statErr := os.Stat(dir); statErr != nil {
+				return output.Wrap(output.CodeNotFound, statErr)
+			}
+		default:
+			return err
+		}
+
+		result, err := inspect.Inspect(root, project)
+		if err != nil {
+			return output.Wrap(output.CodeIO, err)
+		}
+		for _, w := range result.Warnings {
+			report.Warn(w)
+		}
+		report.Data = result
+		if jsonOutput() {
+			return nil // The result document carries the report
+		}
+
+		switch inspectFormat {
+		case "table":
+			return result.WriteTable(os.Stdout)
+		case "json":
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(result)
+		case "mermaid":
+			return result.WriteMermaid(os.Stdout)
+		}
+		return output.Errorf(output.CodeInvalidArgument, "invalid --format '%s' (expected table, json or mermaid)", inspectFormat)
+	},
+}
+
+func init() {
+	inspectCmd.Flags().StringVarP(&inspectFormat, "format", "f", "table", "Output format: table | json | mermaid")
+}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/godamri/helix-cli/internal/output"
 	"github.com/spf13/cobra"
 )
 
@@ -34,7 +35,7 @@ var diffCmd = &cobra.Command{
 
 		// Check Prerequisites
 		if _, err := exec.LookPath("docker"); err != nil {
-			return fmt.Errorf("'docker' binary not found. This CLI relies on Docker.")
+			return output.Errorf(output.CodeNotFound, "'docker' binary not found. This CLI relies on Docker.")
 		}
 
 		cwd, err := os.Getwd()
@@ -44,7 +45,7 @@ var diffCmd = &cobra.Command{
 
 		// Ensure we are at project root
 		if _, err := os.Stat(filepath.Join(cwd, "docker-compose.yml")); os.IsNotExist(err) {
-			return fmt.Errorf("docker-compose.yml not found. Please run this command from the project root (e.g., inside svc-order/).")
+			return output.Errorf(output.CodeNotFound, "docker-compose.yml not found. Please run this command from the project root (e.g., inside svc-order/).")
 		}
 
 		// Derive Context (Project Name & DB Name)
@@ -84,14 +85,14 @@ func init() {
 // runCommand helper to execute shell commands with stdout/stderr attached
 func runCommand(name string, args ...string) error {
 	cmd := exec.Command(name, args...)
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = humanOut()
 	cmd.Stderr = os.Stderr
 	cmd.Stdin = os.Stdin
 
 	slog.Debug("Executing", "cmd", name, "args", strings.Join(args, " "))
 
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("command execution failed: %w", err)
+		return output.Errorf(output.CodeCommandFailed, "command execution failed: %w", err)
 	}
 	return nil
 }
@@ -9,19 +9,28 @@ import (
 	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
+	"github.com/godamri/helix-cli/internal/hooks"
+	"github.com/godamri/helix-cli/internal/manifest"
+	"github.com/godamri/helix-cli/internal/output"
+	"github.com/godamri/helix-cli/internal/scaffold"
 	"github.com/godamri/helix-cli/internal/template"
 	"github.com/spf13/cobra"
 )
 
 var newCmd = &cobra.Command{
 	Use:   "new",
-	Short: "Generate new components (entity, event, handler) in an existing project",
-	Long:  `The 'new' command is used for scaffolding new domain entities and components.`,
+	Short: "Generate new components (entity, consumer, saga, job, cache, outbox-partitioning) in an existing project",
+	Long: `The 'new' command scaffolds components into an existing project: domain
+entities, Kafka consumers, sagas, scheduled jobs, cached repositories and the
+outbox partitioning migration.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cmd.Help()
 	},
 }
 
+// entityDriver replaces the driver prompt of 'new entity' (required with --output json).
+var entityDriver string
+
 var newEntityCmd = &cobra.Command{
 	Use:   "entity [name]",
 	Short: "Generate a new Domain Entity",
@@ -29,8 +38,6 @@ var newEntityCmd = &cobra.Command{
 Note: You must manually wire the dependencies in main.go (Explicit > Implicit).`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
-
 		rawName := args[0]
 		entityNameTitle := kebabToPascal(rawName)
 		entityNameCamel := kebabToCamel(rawName)
@@ -38,14 +45,14 @@ Note: You must manually wire the dependencies in main.go (Explicit > Implicit).`
 		entityPluralNameLower := fmt.Sprintf("%ss", entityNameLower)
 		entityFileName := strings.ReplaceAll(rawName, "-", "_")
 
-		var driver string
+		driver := entityDriver
 		promptDriver := &survey.Select{
 			Message: "Which driver should this entity use?",
 			Options: []string{"ent", "pgx"},
 			Default: "ent",
 			Help:    "Select 'ent' for standard ORM or 'pgx' for raw SQL repository.",
 		}
-		if err := survey.AskOne(promptDriver, &driver); err != nil {
+		if err := askSelect(&driver, "driver", promptDriver); err != nil {
 			return err
 		}
 
@@ -61,67 +68,117 @@ Note: You must manually wire the dependencies in main.go (Explicit > Implicit).`
 			Driver:            driver, // Pass choice
 		}
 
-		if TemplateFS == nil {
-			return fmt.Errorf("embedded template FS is nil")
+		fetcher, err := templateFetcher()
+		if err != nil {
+			return err
 		}
-		fetcher := template.NewSmartFetcher(TemplateFS, logger)
 		gen := template.NewGenerator(data, fetcher)
 
-		files := map[string]string{
-			"templates/entity/entity.go.tmpl": filepath.Join(wd, "internal", "core", "entity", fmt.Sprintf("%s.go", entityFileName)),
-			// DTO v1
-			"templates/entity/dto.go.tmpl":             filepath.Join(wd, "internal", "core", "dto", "v1", fmt.Sprintf("%s.go", entityFileName)),
-			"templates/entity/port_service.go.tmpl":    filepath.Join(wd, "internal", "core", "port", fmt.Sprintf("%s_service.go", entityFileName)),
-			"templates/entity/port_repository.go.tmpl": filepath.Join(wd, "internal", "core", "port", fmt.Sprintf("%s_repository.go", entityFileName)),
-			"templates/entity/service_impl.go.tmpl":    filepath.Join(wd, "internal", "core", "service", fmt.Sprintf("%s_service.go", entityFileName)),
-			"templates/entity/repo_impl.go.tmpl":       filepath.Join(wd, "internal", "adapter", "repository", fmt.Sprintf("%s_repository.go", entityFileName)),
-			// Handler v1
-			"templates/entity/handler_impl.go.tmpl": filepath.Join(wd, "internal", "adapter", "handler", "v1", fmt.Sprintf("%s_handler.go", entityFileName)),
-			"templates/entity/ent_schema.go.tmpl":   filepath.Join(wd, "ent", "schema", fmt.Sprintf("%s.go", entityFileName)),
+		files := scaffold.EntityFiles(wd, entityFileName)
+
+		project, err := manifest.FindProject(wd)
+		if err != nil {
+			slog.Debug("No project manifest, skipping registration", "error", err)
+			project = nil
+		}
+		pack, err := manifest.LoadPack(fetcher)
+		if err != nil {
+			return err
+		}
+		hookRunner := newHookRunner(pack, project)
+
+		planned := make([]string, 0, len(files))
+		for _, dest := range files {
+			planned = append(planned, dest)
+		}
+		hookCtx := hooks.Context{Command: "new entity", Root: wd, Files: hooks.RelativeFiles(wd, planned)}
+
+		if err := hookRunner.Run(manifest.PhasePreRender, hookCtx); err != nil {
+			return err
 		}
 
 		slog.Info("Generating entity files...", "entity", entityNameTitle, "driver", driver)
 		for src, dest := range files {
+			_, statErr := os.Stat(dest)
 			if err := gen.ProcessFile(src, dest); err != nil {
-				return fmt.Errorf("gen failed for %s: %w", dest, err)
+				return output.Errorf(output.CodeTemplate, "gen failed for %s: %w", dest, err)
+			}
+			if statErr == nil {
+				report.Modified(dest)
+			} else {
+				report.Created(dest)
 			}
 		}
 
+		if project != nil {
+			project.AddEntity(manifest.Entity{Name: entityNameTitle, Driver: driver})
+			recordSources(project, fetcher, files)
+			if err := project.Save(); err != nil {
+				slog.Warn("Failed to update project manifest", "error", err)
+				report.Warn(fmt.Sprintf("failed to update project manifest: %v", err))
+			} else {
+				report.Modified(filepath.Join(project.Root, manifest.ProjectFile))
+			}
+		}
+
+		if err := hookRunner.Run(manifest.PhasePostRender, hookCtx); err != nil {
+			return err
+		}
+
+		refreshEventSchemas(wd)
+		refreshAsyncAPI(wd)
+
 		slog.Info("Running go generate & tidy...")
 		if err := exec.Command("go", "generate", "./ent/...").Run(); err != nil {
 			slog.Warn("go generate failed (check ent schema)", "error", err)
+			report.Warn(fmt.Sprintf("go generate failed (check ent schema): %v", err))
 		}
 		exec.Command("go", "mod", "tidy").Run()
 
 		printWiringInstructions(entityNameTitle, entityNameCamel, driver)
-		return nil
+		report.Next("Wire the pending dependencies in 'cmd/server/main.go'")
+
+		return hookRunner.Run(manifest.PhasePostWire, hookCtx)
 	},
 }
 
+// printWiringInstructions prints the manual wiring for main.go and records it as pending.
 func printWiringInstructions(name, camel, driver string) {
-	fmt.Println("\nEntity generated successfully (Version: v1)!")
-	fmt.Println("ACTION REQUIRED: Wire dependencies in 'cmd/server/main.go'")
-	fmt.Println("---------------------------------------------------------")
-	fmt.Printf("// Imports\n")
-	fmt.Printf("import handlerV1 \".../internal/adapter/handler/v1\"\n\n")
-
-	fmt.Printf("// Repository (%s)\n", strings.ToUpper(driver))
+	repoArg := "stdMainDB"
 	if driver == "ent" {
-		fmt.Printf("repo%s := repository.New%sRepository(entClient)\n", name, name)
-	} else {
-		fmt.Printf("repo%s := repository.New%sRepository(stdMainDB)\n", name, name)
+		repoArg = "entClient"
 	}
-	fmt.Printf("svc%s := service.New%sService(repo%s, outboxRepo, txManager)\n\n", name, name, name)
+	pending := []string{
+		"import handlerV1 \".../internal/adapter/handler/v1\"",
+		fmt.Sprintf("repo%s := repository.New%sRepository(%s)", name, name, repoArg),
+		fmt.Sprintf("svc%s := service.New%sService(repo%s, outboxRepo, txManager)", name, name, name),
+		fmt.Sprintf("h%sV1 := handlerV1.New%sHandler(svc%s)", name, name, name),
+		fmt.Sprintf("r.Route(\"/v1/%ss\", func(r chi.Router) {\n\tr.Post(\"/\", h%sV1.Create)\n\tr.Get(\"/{id}\", h%sV1.GetByID)\n})", camel, name, name),
+	}
+	for _, code := range pending {
+		report.Pending(code)
+	}
+
+	printLine("\nEntity generated successfully (Version: v1)!")
+	printLine("ACTION REQUIRED: Wire dependencies in 'cmd/server/main.go'")
+	printLine("---------------------------------------------------------")
+	printf("// Imports\n")
+	printf("%s\n\n", pending[0])
 
-	fmt.Printf("// Handler (V1)\n")
-	fmt.Printf("h%sV1 := handlerV1.New%sHandler(svc%s)\n", name, name, name)
+	printf("// Repository (%s)\n", strings.ToUpper(driver))
+	printf("%s\n", pending[1])
+	printf("%s\n\n", pending[2])
+
+	printf("// Handler (V1)\n")
+	printf("%s\n", pending[3])
+
+	printf("// Route (V1)\n")
+	printf("%s\n", pending[4])
+	printLine("---------------------------------------------------------")
+}
 
-	fmt.Printf("// Route (V1)\n")
-	fmt.Printf("r.Route(\"/v1/%ss\", func(r chi.Router) {\n", camel)
-	fmt.Printf("\tr.Post(\"/\", h%sV1.Create)\n", name)
-	fmt.Printf("\tr.Get(\"/{id}\", h%sV1.GetByID)\n", name)
-	fmt.Printf("})\n")
-	fmt.Println("---------------------------------------------------------")
+func init() {
+	newEntityCmd.Flags().StringVar(&entityDriver, "driver", "", "Database driver: ent | pgx (skips the prompt)")
 }
 
 func getGoModuleName(wd string) string {
@@ -1,14 +1,13 @@
 package cmd
 
 import (
-	"bytes"
 	"fmt"
-	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
-	"text/template"
 
+	"github.com/godamri/helix-cli/internal/output"
+	"github.com/godamri/helix-cli/internal/scaffold"
 	helixTemplate "github.com/godamri/helix-cli/internal/template"
 	"github.com/spf13/cobra"
 )
@@ -19,8 +18,6 @@ var newCacheCmd = &cobra.Command{
 	Short: "Generate a new Cache/Redis Repository",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
-
 		rawName := args[0]
 		structName := kebabToPascal(rawName)
 		fileName := fmt.Sprintf("%s_cache.go", strings.ReplaceAll(strings.ToLower(rawName), "-", "_"))
@@ -30,48 +27,41 @@ var newCacheCmd = &cobra.Command{
 		targetFile := filepath.Join(targetDir, fileName)
 
 		if _, err := os.Stat(targetFile); err == nil {
-			return fmt.Errorf("cache file '%s' already exists", fileName)
+			return output.Errorf(output.CodeAlreadyExists, "cache file '%s' already exists", fileName)
 		}
 
-		data := struct {
-			StructName      string
-			LowerStructName string
-		}{
+		data := scaffold.CacheData{
 			StructName:      structName,
 			LowerStructName: strings.ToLower(structName),
 		}
 
-		// Use TemplateFS & SmartFetcher
-		if TemplateFS == nil {
-			return fmt.Errorf("embedded template FS is nil")
+		fetcher, err := templateFetcher()
+		if err != nil {
+			return err
 		}
-		fetcher := helixTemplate.NewSmartFetcher(TemplateFS, logger)
 
-		tmplPath := "templates/cache/cache.go.tmpl"
+		tmplPath := scaffold.CacheTemplate
 		content, err := fetcher.ReadFile(tmplPath)
 		if err != nil {
-			return fmt.Errorf("failed to read template '%s': %w", tmplPath, err)
+			return output.Errorf(output.CodeTemplate, "failed to read template '%s': %w", tmplPath, err)
 		}
 
-		t, err := template.New("cache").Parse(string(content))
+		rendered, err := helixTemplate.Execute(tmplPath, content, data)
 		if err != nil {
-			return fmt.Errorf("failed to parse template: %w", err)
-		}
-
-		var buf bytes.Buffer
-		if err := t.Execute(&buf, data); err != nil {
-			return fmt.Errorf("failed to execute template: %w", err)
+			return output.Wrap(output.CodeTemplate, err)
 		}
 
 		if err := os.MkdirAll(targetDir, 0755); err != nil {
-			return fmt.Errorf("failed to create directory: %w", err)
+			return output.Errorf(output.CodeIO, "failed to create directory: %w", err)
 		}
 
-		if err := os.WriteFile(targetFile, buf.Bytes(), 0644); err != nil {
-			return fmt.Errorf("failed to write file: %w", err)
+		if err := os.WriteFile(targetFile, rendered, 0644); err != nil {
+			return output.Errorf(output.CodeIO, "failed to write file: %w", err)
 		}
 
-		fmt.Printf("Cache repository '%s' generated at %s\n", structName, targetFile)
+		printf("Cache repository '%s' generated at %s\n", structName, targetFile)
+		report.Created(targetFile)
+		recordGenerated(fetcher, tmplPath, targetFile)
 		return nil
 	},
 }
@@ -1,26 +1,64 @@
 package cmd
 
 import (
-	"bytes"
+	"errors"
 	"fmt"
-	"log/slog"
+	"go/format"
 	"os"
 	"path/filepath"
 	"strings"
-	"text/template"
+	"time"
 
+	"github.com/godamri/helix-cli/internal/events"
+	"github.com/godamri/helix-cli/internal/manifest"
+	"github.com/godamri/helix-cli/internal/output"
+	"github.com/godamri/helix-cli/internal/scaffold"
 	helixTemplate "github.com/godamri/helix-cli/internal/template"
 	"github.com/spf13/cobra"
 )
 
+var (
+	// consumerRegistryFile is rendered from the manifest consumers; never edit it by hand.
+	consumerRegistryFile = filepath.Join("internal", "adapter", "worker", "consumers_gen.go")
+	// consumerRuntimeFile holds ConsumerSpec, which consumers_gen.go builds on.
+	consumerRuntimeFile = filepath.Join("internal", "adapter", "worker", "consumer.go")
+)
+
+// Flags of 'new consumer'.
+var (
+	consumerEvent       string
+	consumerGroup       string
+	consumerRetries     int
+	consumerBackoff     time.Duration
+	consumerMaxBackoff  time.Duration
+	consumerDLQ         string
+	consumerNoDLQ       bool
+	consumerConcurrency int
+	consumerHashIDs     bool
+)
+
 var newConsumerCmd = &cobra.Command{
-	Use:     "consumer [name] [topic]",
-	Short:   "Generate a new Kafka Consumer Handler",
-	Example: "  helix-cli new consumer UserCreated user.events.created",
-	Args:    cobra.ExactArgs(2),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	Use:   "consumer [name] [topic]",
+	Short: "Generate a new Kafka Consumer Handler",
+	Long: `Generates internal/adapter/worker/consumer_<name>.go and declares the consumer in
+.helix/manifest.json. consumers_gen.go is re-rendered from the manifest, so
+cmd/server/main.go registers the consumer without further wiring.
+
+The payload is decoded into a typed event: the contract in internal/core/event
+whose topic matches (or --event), else a local struct to fill in. Handler errors
+are classified: Permanent errors dead-letter at once, Skip errors are dropped,
+anything else is retried with backoff and then dead-lettered.
 
+Redeliveries are deduplicated on the CloudEvents id of a structured envelope,
+the outbox row ID. Messages without one (binary or raw CLOUDEVENTS_MODE, other
+producers) fail for good, unless --hash-ids keys them on a hash of key and
+payload instead.`,
+	Example: `  helix-cli new consumer UserCreated user.events.created
+  helix-cli new consumer order-paid order.paid --retries 5 --backoff 2s --concurrency 4
+  helix-cli new consumer AuditTrail audit.events --no-dlq --group audit
+  helix-cli new consumer PartnerOrder partner.orders --hash-ids`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
 		rawName := args[0]
 		topic := args[1]
 
@@ -28,60 +66,193 @@ var newConsumerCmd = &cobra.Command{
 		fileName := fmt.Sprintf("consumer_%s.go", strings.ReplaceAll(strings.ToLower(rawName), "-", "_"))
 
 		wd, _ := os.Getwd()
-		targetDir := filepath.Join(wd, "internal", "adapter", "worker")
-		targetFile := filepath.Join(targetDir, fileName)
+		project, err := manifest.FindProject(wd)
+		switch {
+		case err == nil:
+			wd = project.Root
+		case errors.Is(err, manifest.ErrNoProject):
+			project = nil
+		default:
+			return err
+		}
+		targetFile := filepath.Join(wd, "internal", "adapter", "worker", fileName)
 
 		// Check overlap
 		if _, err := os.Stat(targetFile); err == nil {
-			return fmt.Errorf("consumer file '%s' already exists", fileName)
+			return output.Errorf(output.CodeAlreadyExists, "consumer file '%s' already exists", fileName)
 		}
 
-		// Prepare Data - Match with templates/consumer/consumer.go.tmpl
-		data := struct {
-			EntityName   string
-			Topic        string
-			GoModuleName string
-		}{
-			EntityName:   consumerName,
-			Topic:        topic,
-			GoModuleName: getGoModuleName(wd),
+		fetcher, err := templateFetcher()
+		if err != nil {
+			return err
 		}
 
-		// TemplateFS is an interface (fs.FS), so check against nil.
-		if TemplateFS == nil {
-			return fmt.Errorf("embedded template FS is nil")
+		if _, err := os.Stat(filepath.Join(wd, consumerRuntimeFile)); project == nil || err != nil {
+			// Services generated before declared consumers have no consumers_gen.go to add to.
+			data := scaffold.ConsumerData{EntityName: consumerName, Topic: topic, GoModuleName: getGoModuleName(wd)}
+			if err := renderConsumer(fetcher, targetFile, data); err != nil {
+				return err
+			}
+			printf("Consumer '%s' generated at %s\n", consumerName, targetFile)
+			report.Warn(fmt.Sprintf("%s not found: the consumer needs worker.Decode and the error classification of a current scaffold", consumerRuntimeFile))
+			report.Pending(fmt.Sprintf("register the %s consumer (topic %s) with consumerMgr in cmd/server/main.go, wrapped in worker.Idempotent", consumerName, topic))
+			return nil
 		}
-		fetcher := helixTemplate.NewSmartFetcher(TemplateFS, logger)
 
-		tmplPath := "templates/consumer/consumer.go.tmpl"
-		content, err := fetcher.ReadFile(tmplPath)
-		if err != nil {
-			return fmt.Errorf("failed to read template '%s': %w", tmplPath, err)
+		for _, c := range project.Consumers {
+			if c.Name == consumerName {
+				return output.Errorf(output.CodeAlreadyExists, "consumer '%s' is already declared in %s", consumerName, manifest.ProjectFile)
+			}
 		}
 
-		// Parse & Execute
-		t, err := template.New("consumer").Parse(string(content))
+		eventType, err := consumerEventType(project.Root, topic, consumerEvent)
 		if err != nil {
-			return fmt.Errorf("failed to parse template: %w", err)
+			return err
 		}
 
-		var buf bytes.Buffer
-		if err := t.Execute(&buf, data); err != nil {
-			return fmt.Errorf("failed to execute template: %w", err)
+		c := manifest.Consumer{
+			Name:           consumerName,
+			Topic:          topic,
+			Event:          eventType,
+			Group:          consumerGroup,
+			MaxRetries:     consumerRetries,
+			InitialBackoff: consumerBackoff.String(),
+			MaxBackoff:     consumerMaxBackoff.String(),
+			DLQ:            consumerDLQ,
+			Concurrency:    consumerConcurrency,
+			HashIDs:        consumerHashIDs,
 		}
-
-		// Write File
-		if err := os.MkdirAll(targetDir, 0755); err != nil {
-			return fmt.Errorf("failed to create directory: %w", err)
+		if c.DLQ == "" && !consumerNoDLQ {
+			c.DLQ = topic + ".dlq"
 		}
-
-		if err := os.WriteFile(targetFile, buf.Bytes(), 0644); err != nil {
-			return fmt.Errorf("failed to write file: %w", err)
+		if c.Concurrency < 1 {
+			return output.Errorf(output.CodeInvalidArgument, "--concurrency must be at least 1")
 		}
 
-		fmt.Printf("Consumer '%s' generated at %s\n", consumerName, targetFile)
-		fmt.Println("Don't forget to register it in 'cmd/server/main.go'!")
+		if err := addConsumer(project, fetcher, c, targetFile); err != nil {
+			return err
+		}
+		if err := project.Save(); err != nil {
+			return output.Errorf(output.CodeIO, "write project manifest: %w", err)
+		}
+		refreshAsyncAPI(project.Root)
 
+		printf("Consumer '%s' generated at %s and declared in %s\n", consumerName, targetFile, manifest.ProjectFile)
+		if eventType != "" {
+			printf("Payload type: event.%s\n", eventType)
+		}
+		report.Pending(fmt.Sprintf("implement worker.%sProcessor and set it in worker.Processors{%s: ...} in cmd/server/main.go (without it the consumer only logs and acknowledges)", consumerName, consumerName))
 		return nil
 	},
 }
+
+func init() {
+	f := newConsumerCmd.Flags()
+	f.StringVar(&consumerEvent, "event", "", "Contract type in internal/core/event to decode into (default: the one published on the topic, if any)")
+	f.StringVar(&consumerGroup, "group", "", "Consumer group (default: <SERVICE_NAME>-<name>)")
+	f.IntVar(&consumerRetries, "retries", 3, "Retries of a transient failure before dead-lettering")
+	f.DurationVar(&consumerBackoff, "backoff", time.Second, "Initial retry backoff")
+	f.DurationVar(&consumerMaxBackoff, "max-backoff", 5*time.Second, "Maximum retry backoff")
+	f.StringVar(&consumerDLQ, "dlq", "", "Dead-letter topic (default: <topic>.dlq)")
+	f.BoolVar(&consumerNoDLQ, "no-dlq", false, "Drop messages that fail for good instead of dead-lettering them")
+	f.IntVar(&consumerConcurrency, "concurrency", 1, "Consumers of the group started on each replica")
+	f.BoolVar(&consumerHashIDs, "hash-ids", false, "Deduplicate on a hash of key and payload, for messages without a CloudEvents envelope (identical events are processed once)")
+	newConsumerCmd.MarkFlagsMutuallyExclusive("dlq", "no-dlq")
+}
+
+// consumerEventType resolves the contract a consumer of topic decodes into.
+// Without --event it is the contract published on topic, if exactly one is.
+func consumerEventType(root, topic, want string) (string, error) {
+	contracts, err := events.Load(root)
+	if err != nil {
+		if want != "" {
+			return "", output.Wrap(output.CodeInvalidArgument, err)
+		}
+		report.Warn(fmt.Sprintf("event contracts not loaded, the consumer gets a local payload struct: %v", err))
+		return "", nil
+	}
+
+	var onTopic []string
+	for _, c := range contracts {
+		if want != "" && c.Name == want {
+			return want, nil
+		}
+		if c.Topic == topic {
+			onTopic = append(onTopic, c.Name)
+		}
+	}
+	switch {
+	case want != "":
+		return "", output.Errorf(output.CodeNotFound, "no event contract '%s' in %s", want, events.Dir)
+	case len(onTopic) == 1:
+		return onTopic[0], nil
+	case len(onTopic) > 1:
+		report.Warn(fmt.Sprintf("several contracts are published on %s (%s); pick one with --event", topic, strings.Join(onTopic, ", ")))
+	}
+	return "", nil
+}
+
+// addConsumer renders the handler of c into file, declares c in the project and
+// re-renders consumers_gen.go. The caller saves the manifest.
+func addConsumer(project *manifest.Project, fetcher *helixTemplate.SmartFetcher, c manifest.Consumer, file string) error {
+	data := scaffold.ConsumerData{EntityName: c.Name, Topic: c.Topic, GoModuleName: project.Module, Event: c.Event}
+	if err := renderConsumer(fetcher, file, data); err != nil {
+		return err
+	}
+	project.AddConsumer(c)
+	recordSources(project, fetcher, map[string]string{scaffold.ConsumerTemplate: file})
+	return renderConsumerRegistry(project, fetcher)
+}
+
+// renderConsumerRegistry writes consumers_gen.go from the manifest consumers.
+func renderConsumerRegistry(project *manifest.Project, fetcher *helixTemplate.SmartFetcher) error {
+	data, err := scaffold.NewConsumerRegistryData(project.Module, project.Consumers)
+	if err != nil {
+		return output.Wrap(output.CodeInvalidArgument, err)
+	}
+	file := filepath.Join(project.Root, consumerRegistryFile)
+	_, statErr := os.Stat(file)
+	if err := renderFile(fetcher, scaffold.ConsumerRegistryTemplate, file, data); err != nil {
+		return err
+	}
+	if statErr == nil {
+		report.Modified(file)
+	} else {
+		report.Created(file)
+	}
+	recordSources(project, fetcher, map[string]string{scaffold.ConsumerRegistryTemplate: file})
+	return nil
+}
+
+func renderConsumer(fetcher *helixTemplate.SmartFetcher, file string, data scaffold.ConsumerData) error {
+	if err := renderFile(fetcher, scaffold.ConsumerTemplate, file, data); err != nil {
+		return err
+	}
+	report.Created(file)
+	return nil
+}
+
+// renderFile executes a single-file template into dest, gofmt'ed.
+func renderFile(fetcher *helixTemplate.SmartFetcher, tmplPath, dest string, data interface{}) error {
+	content, err := fetcher.ReadFile(tmplPath)
+	if err != nil {
+		return output.Errorf(output.CodeTemplate, "failed to read template '%s': %w", tmplPath, err)
+	}
+	rendered, err := helixTemplate.Execute(tmplPath, content, data)
+	if err != nil {
+		return output.Wrap(output.CodeTemplate, err)
+	}
+	if strings.HasSuffix(dest, ".go") {
+		// Generated lists don't align their fields; overrides that don't parse are written as is.
+		if formatted, err := format.Source(rendered); err == nil {
+			rendered = formatted
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return output.Errorf(output.CodeIO, "failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(dest, rendered, 0644); err != nil {
+		return output.Errorf(output.CodeIO, "failed to write file: %w", err)
+	}
+	return nil
+}
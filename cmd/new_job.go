@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	helixAst "github.com/godamri/helix-cli/internal/ast"
+	"github.com/godamri/helix-cli/internal/cron"
+	"github.com/godamri/helix-cli/internal/manifest"
+	"github.com/godamri/helix-cli/internal/output"
+	"github.com/godamri/helix-cli/internal/scaffold"
+	helixTemplate "github.com/godamri/helix-cli/internal/template"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// jobRegistryFile is rendered from the manifest jobs; never edit it by hand.
+	jobRegistryFile = filepath.Join("internal", "adapter", "worker", "jobs_gen.go")
+	// jobRuntimeFile is rendered with the first job of a service.
+	jobRuntimeFile = filepath.Join("internal", "adapter", "worker", "scheduler.go")
+)
+
+// Flags of 'new job'.
+var (
+	jobSchedule string
+	jobTimeout  time.Duration
+)
+
+var newJobCmd = &cobra.Command{
+	Use:   "job [name]",
+	Short: "Generate a scheduled job run by the worker on a cron schedule",
+	Long: `Generates internal/adapter/worker/job_<name>.go and declares the job in
+.helix/manifest.json. jobs_gen.go is re-rendered from the manifest and the
+scheduler in cmd/server/main.go (APP_MODE all or worker) runs every declared job.
+
+Schedules are five-field cron expressions in UTC. Every replica schedules the
+job, but each tick is claimed in the job_runs table first, so it runs once
+across replicas; a tick is also skipped while the previous run is in progress.
+job_runs keeps the history of every run (status, replica, error), and runs are
+cancelled after --timeout.
+
+The first job of a service also adds the scheduler (worker/scheduler.go and
+cron.go, the job_runs table and its repository) and wires it into main.go.`,
+	Example: `  helix-cli new job expire-sessions --schedule "*/5 * * * *"
+  helix-cli new job monthly-invoices --schedule "0 2 1 * *" --timeout 30m
+  helix-cli new job cleanup --schedule @daily`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rawName := args[0]
+		jobName := kebabToPascal(rawName)
+		fileName := fmt.Sprintf("job_%s.go", strings.ReplaceAll(strings.ToLower(rawName), "-", "_"))
+
+		schedule, err := cron.Parse(jobSchedule)
+		if err != nil {
+			return output.Errorf(output.CodeInvalidArgument, "--schedule: %v", err)
+		}
+		if jobTimeout <= 0 {
+			return output.Errorf(output.CodeInvalidArgument, "--timeout must be positive")
+		}
+
+		wd, _ := os.Getwd()
+		project, err := manifest.FindProject(wd)
+		if errors.Is(err, manifest.ErrNoProject) {
+			return output.Errorf(output.CodeNotFound, "no %s found: run 'new job' inside a service generated by helix-cli", manifest.ProjectFile)
+		}
+		if err != nil {
+			return err
+		}
+		root := project.Root
+
+		targetFile := filepath.Join(root, "internal", "adapter", "worker", fileName)
+		if _, err := os.Stat(targetFile); err == nil {
+			return output.Errorf(output.CodeAlreadyExists, "job file '%s' already exists", fileName)
+		}
+		for _, j := range project.Jobs {
+			if j.Name == jobName {
+				return output.Errorf(output.CodeAlreadyExists, "job '%s' is already declared in %s", jobName, manifest.ProjectFile)
+			}
+		}
+
+		fetcher, err := templateFetcher()
+		if err != nil {
+			return err
+		}
+		data := scaffold.JobData{GoModuleName: project.Module, Name: jobName}
+
+		newRuntime := false
+		if _, err := os.Stat(filepath.Join(root, jobRuntimeFile)); err != nil {
+			runtime := scaffold.JobRuntimeFiles(root)
+			for tmpl, dest := range runtime {
+				if err := renderFile(fetcher, tmpl, dest, data); err != nil {
+					return err
+				}
+				report.Created(dest)
+			}
+			recordSources(project, fetcher, runtime)
+			newRuntime = true
+		}
+		if err := renderFile(fetcher, scaffold.JobTemplate, targetFile, data); err != nil {
+			return err
+		}
+		report.Created(targetFile)
+		recordSources(project, fetcher, map[string]string{scaffold.JobTemplate: targetFile})
+
+		project.AddJob(manifest.Job{Name: jobName, Schedule: jobSchedule, Timeout: jobTimeout.String()})
+		if err := renderJobRegistry(project, fetcher); err != nil {
+			return err
+		}
+		if err := project.Save(); err != nil {
+			return output.Errorf(output.CodeIO, "write project manifest: %w", err)
+		}
+
+		wireScheduler(root)
+
+		now := time.Now()
+		var next []string
+		for t := schedule.Next(now); len(next) < 3; t = schedule.Next(t) {
+			next = append(next, t.Format("2006-01-02 15:04"))
+		}
+		printf("Job '%s' generated at %s and declared in %s\n", jobName, targetFile, manifest.ProjectFile)
+		printf("Next runs (UTC): %s\n", strings.Join(next, ", "))
+
+		if newRuntime {
+			slog.Info("Running go generate for the job_runs schema...")
+			gen := exec.Command("go", "generate", "./ent/...")
+			gen.Dir = root
+			if err := gen.Run(); err != nil {
+				report.Warn(fmt.Sprintf("go generate failed (check ent schema): %v", err))
+			}
+			report.Next("Create the job_runs table: make migrate-diff name=add_job_runs")
+		}
+		report.Pending(fmt.Sprintf("implement worker.%sRunner and set it in jobRunners (worker.JobRunners{%s: ...}) in cmd/server/main.go (without it the job only logs its runs)", jobName, jobName))
+		return nil
+	},
+}
+
+func init() {
+	f := newJobCmd.Flags()
+	f.StringVar(&jobSchedule, "schedule", "", `Cron schedule in UTC, e.g. "*/5 * * * *" or @daily`)
+	f.DurationVar(&jobTimeout, "timeout", 5*time.Minute, "Deadline of each run")
+	newJobCmd.MarkFlagRequired("schedule")
+}
+
+// renderJobRegistry writes jobs_gen.go from the manifest jobs.
+func renderJobRegistry(project *manifest.Project, fetcher *helixTemplate.SmartFetcher) error {
+	data, err := scaffold.NewJobRegistryData(project.Module, project.Jobs)
+	if err != nil {
+		return output.Wrap(output.CodeInvalidArgument, err)
+	}
+	file := filepath.Join(project.Root, jobRegistryFile)
+	_, statErr := os.Stat(file)
+	if err := renderFile(fetcher, scaffold.JobRegistryTemplate, file, data); err != nil {
+		return err
+	}
+	if statErr == nil {
+		report.Modified(file)
+	} else {
+		report.Created(file)
+	}
+	recordSources(project, fetcher, map[string]string{scaffold.JobRegistryTemplate: file})
+	return nil
+}
+
+// wireScheduler starts the scheduler and its janitor in the worker of
+// cmd/server/main.go; later jobs find the blocks already there. main.go files
+// that predate the injection points get the wiring as pending.
+func wireScheduler(root string) {
+	injections := []helixAst.Injection{
+		{Point: "wiring", Code: "jobRunRepo := repository.NewJobRunRepository(stdWorkerDB)"},
+		{Point: "wiring", Code: "jobRunners := worker.JobRunners{}"},
+		{Point: "workers", Code: "scheduler, err := worker.NewScheduler(jobRunRepo, worker.DeclaredJobs(jobRunners))\n" +
+			"if err != nil {\n\treturn fmt.Errorf(\"failed to init scheduler: %w\", err)\n}\n" +
+			"g.Go(func() error {\n\tscheduler.Start(groupCtx)\n\treturn nil\n})"},
+		{Point: "workers", Code: "g.Go(func() error {\n\tworker.NewJobRunJanitor(jobRunRepo).Start(groupCtx)\n\treturn nil\n})"},
+	}
+
+	mainFile := filepath.Join(root, "cmd", "server", "main.go")
+	if src, err := os.ReadFile(mainFile); err == nil && strings.Contains(string(src), "worker.NewScheduler(") {
+		return
+	}
+	if _, err := helixAst.NewInjector(mainFile).Apply(injections); err != nil {
+		report.Warn(fmt.Sprintf("%s not wired: %v", relTo(root, mainFile), err))
+		for _, inj := range injections {
+			report.Pending(inj.Code)
+		}
+		return
+	}
+	report.Modified(mainFile)
+	report.Applied(fmt.Sprintf("scheduler started in the worker of %s", relTo(root, mainFile)))
+
+	if cfg, err := os.ReadFile(filepath.Join(root, "internal", "pkg", "config", "config.go")); err == nil && !strings.Contains(string(cfg), "JOB_HISTORY_RETENTION") {
+		report.Pending("add JobHistoryRetention time.Duration `envconfig:\"JOB_HISTORY_RETENTION\" default:\"720h\"` to internal/pkg/config.Config")
+	}
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/godamri/helix-cli/internal/output"
+	"github.com/godamri/helix-cli/internal/scaffold"
+	helixTemplate "github.com/godamri/helix-cli/internal/template"
+	"github.com/spf13/cobra"
+)
+
+var outboxPremakeDays int
+
+var newOutboxPartitioningCmd = &cobra.Command{
+	Use:   "outbox-partitioning",
+	Short: "Generate a migration that partitions the outbox table by day",
+	Long: `Writes an Atlas migration converting 'outboxes' into a table range-partitioned
+by day on created_at. Once applied, set WORKER_OUTBOX_PARTITIONED=true so the
+retention janitor creates upcoming partitions and drops (or detaches) expired ones.`,
+	Example: "  helix-cli new outbox-partitioning --premake 7",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if outboxPremakeDays < 0 {
+			return output.Errorf(output.CodeInvalidArgument, "--premake must not be negative")
+		}
+
+		wd, _ := os.Getwd()
+		targetDir := filepath.Join(wd, "migrations")
+		if _, err := os.Stat(targetDir); err != nil {
+			return output.Errorf(output.CodeNotFound, "migrations/ not found. Please run this command from the project root (e.g., inside svc-order/).")
+		}
+
+		matches, _ := filepath.Glob(filepath.Join(targetDir, "*_outbox_partitioning.sql"))
+		if len(matches) > 0 {
+			return output.Errorf(output.CodeAlreadyExists, "outbox partitioning migration already exists: %s", filepath.Base(matches[0]))
+		}
+		// Atlas orders migrations by their timestamp prefix.
+		targetFile := filepath.Join(targetDir, time.Now().UTC().Format("20060102150405")+"_outbox_partitioning.sql")
+
+		fetcher, err := templateFetcher()
+		if err != nil {
+			return err
+		}
+
+		tmplPath := scaffold.OutboxPartitionTemplate
+		content, err := fetcher.ReadFile(tmplPath)
+		if err != nil {
+			return output.Errorf(output.CodeTemplate, "failed to read template '%s': %w", tmplPath, err)
+		}
+
+		rendered, err := helixTemplate.Execute(tmplPath, content, scaffold.OutboxPartitionData{PremakeDays: outboxPremakeDays})
+		if err != nil {
+			return output.Wrap(output.CodeTemplate, err)
+		}
+
+		if err := os.WriteFile(targetFile, rendered, 0644); err != nil {
+			return output.Errorf(output.CodeIO, "failed to write file: %w", err)
+		}
+
+		printf("Outbox partitioning migration generated at %s\n", targetFile)
+		report.Created(targetFile)
+		recordGenerated(fetcher, tmplPath, targetFile)
+		report.Next("make migrate-hash")
+		report.Next("make migrate-apply")
+		report.Next("set WORKER_OUTBOX_PARTITIONED=true and WORKER_RETENTION_ENABLED=true on one worker replica")
+		return nil
+	},
+}
+
+func init() {
+	newOutboxPartitioningCmd.Flags().IntVar(&outboxPremakeDays, "premake", 3, "Daily partitions to create ahead of today")
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	helixAst "github.com/godamri/helix-cli/internal/ast"
+	"github.com/godamri/helix-cli/internal/manifest"
+	"github.com/godamri/helix-cli/internal/output"
+	"github.com/godamri/helix-cli/internal/scaffold"
+	"github.com/spf13/cobra"
+)
+
+// sagaRuntimeFile is rendered with the first saga of a service.
+var sagaRuntimeFile = filepath.Join("internal", "adapter", "worker", "saga.go")
+
+// Flags of 'new saga'.
+var (
+	sagaSteps      []string
+	sagaTimeout    time.Duration
+	sagaReplyTopic string
+)
+
+var newSagaCmd = &cobra.Command{
+	Use:   "saga [name]",
+	Short: "Generate a persisted saga with steps, timeouts and compensations",
+	Long: `Generates internal/adapter/worker/saga_<name>.go: a saga (process manager)
+whose state is persisted in the sagas table. Each step sends a command through
+the outbox and waits for the participant's reply on the saga's reply topic.
+A failed step compensates the steps before it, in reverse order; a step without
+a reply after its timeout is compensated as well.
+
+The reply topic is declared as the <Name>Saga consumer in .helix/manifest.json.
+cmd/server/main.go is wired automatically: the saga is built, set as the
+consumer's processor and its timeouts are watched in the worker.
+
+The first saga of a service also adds the runtime (worker/saga.go, the sagas
+table and its repository) and a test harness; saga_<name>_test.go uses it to
+simulate every step succeeding, failing and timing out.`,
+	Example: `  helix-cli new saga order-fulfillment --steps reserve-inventory,charge-payment,ship-order
+  helix-cli new saga refund --steps refund-payment,restock --timeout 2m --reply-topic payments.refund.replies`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rawName := args[0]
+		sagaName := kebabToPascal(rawName)
+		base := strings.ToLower(rawName)
+
+		wd, _ := os.Getwd()
+		project, err := manifest.FindProject(wd)
+		if errors.Is(err, manifest.ErrNoProject) {
+			return output.Errorf(output.CodeNotFound, "no %s found: run 'new saga' inside a service generated by helix-cli", manifest.ProjectFile)
+		}
+		if err != nil {
+			return err
+		}
+		root := project.Root
+		if _, err := os.Stat(filepath.Join(root, consumerRuntimeFile)); err != nil {
+			return output.Errorf(output.CodeIncompatible, "%s not found: sagas need the declared consumers of a current scaffold", consumerRuntimeFile)
+		}
+
+		files := scaffold.SagaFiles(root, strings.ReplaceAll(base, "-", "_"))
+		for _, dest := range files {
+			if _, err := os.Stat(dest); err == nil {
+				return output.Errorf(output.CodeAlreadyExists, "saga file '%s' already exists", relTo(root, dest))
+			}
+		}
+		consumerName := sagaName + "Saga"
+		for _, c := range project.Consumers {
+			if c.Name == consumerName {
+				return output.Errorf(output.CodeAlreadyExists, "consumer '%s' is already declared in %s", consumerName, manifest.ProjectFile)
+			}
+		}
+
+		data := scaffold.SagaData{GoModuleName: project.Module, Name: sagaName, ReplyTopic: sagaReplyTopic}
+		if data.ReplyTopic == "" {
+			data.ReplyTopic = base + ".replies"
+		}
+		seen := map[string]bool{}
+		for _, raw := range sagaSteps {
+			raw = strings.TrimSpace(raw)
+			name := kebabToPascal(raw)
+			if name == "" || seen[name] {
+				return output.Errorf(output.CodeInvalidArgument, "--steps: step names must be non-empty and unique (got '%s')", strings.Join(sagaSteps, ","))
+			}
+			seen[name] = true
+			step, err := scaffold.NewSagaStep(name, base+"."+strings.ToLower(raw), sagaTimeout)
+			if err != nil {
+				return output.Wrap(output.CodeInvalidArgument, err)
+			}
+			data.Steps = append(data.Steps, step)
+		}
+
+		fetcher, err := templateFetcher()
+		if err != nil {
+			return err
+		}
+
+		newRuntime := false
+		if _, err := os.Stat(filepath.Join(root, sagaRuntimeFile)); err != nil {
+			runtime := scaffold.SagaRuntimeFiles(root)
+			for tmpl, dest := range runtime {
+				if err := renderFile(fetcher, tmpl, dest, data); err != nil {
+					return err
+				}
+				report.Created(dest)
+			}
+			recordSources(project, fetcher, runtime)
+			newRuntime = true
+		}
+		for tmpl, dest := range files {
+			if err := renderFile(fetcher, tmpl, dest, data); err != nil {
+				return err
+			}
+			report.Created(dest)
+		}
+		recordSources(project, fetcher, files)
+
+		project.AddConsumer(manifest.Consumer{
+			Name:           consumerName,
+			Topic:          data.ReplyTopic,
+			MaxRetries:     3,
+			InitialBackoff: "1s",
+			MaxBackoff:     "5s",
+			DLQ:            data.ReplyTopic + ".dlq",
+			Concurrency:    1,
+		})
+		if err := renderConsumerRegistry(project, fetcher); err != nil {
+			return err
+		}
+		if err := project.Save(); err != nil {
+			return output.Errorf(output.CodeIO, "write project manifest: %w", err)
+		}
+		refreshAsyncAPI(root)
+
+		wireSaga(root, sagaName)
+
+		printf("Saga '%s' generated with %d step(s); replies on %s\n", sagaName, len(data.Steps), data.ReplyTopic)
+		if newRuntime {
+			slog.Info("Running go generate for the sagas schema...")
+			gen := exec.Command("go", "generate", "./ent/...")
+			gen.Dir = root
+			if err := gen.Run(); err != nil {
+				report.Warn(fmt.Sprintf("go generate failed (check ent schema): %v", err))
+			}
+			report.Next("Create the sagas table: make migrate-diff name=add_sagas")
+		}
+		report.Next(fmt.Sprintf("Fill in worker.%sData and the Command/Compensate payloads in %s", sagaName, relTo(root, files[scaffold.SagaTemplate])))
+		report.Next("Participants handle each command topic with worker.Decode[worker.SagaCommand] and answer with cmd.Reply through their outbox")
+		return nil
+	},
+}
+
+func init() {
+	f := newSagaCmd.Flags()
+	f.StringSliceVar(&sagaSteps, "steps", nil, "Comma-separated step names, in order (e.g. reserve-inventory,charge-payment)")
+	f.DurationVar(&sagaTimeout, "timeout", 30*time.Second, "Reply timeout of every step (0 waits forever)")
+	f.StringVar(&sagaReplyTopic, "reply-topic", "", "Topic participants reply on (default: <name>.replies)")
+	newSagaCmd.MarkFlagRequired("steps")
+}
+
+// wireSaga injects the saga into cmd/server/main.go, or records the wiring as
+// pending when main.go predates the injection points.
+func wireSaga(root, name string) {
+	v := kebabToCamel(name) + "Saga"
+	injections := []helixAst.Injection{
+		{Point: "wiring", Code: "sagaRepo := repository.NewSagaRepository(stdMainDB)"},
+		{Point: "wiring", Code: fmt.Sprintf("%s := worker.New%sSaga(sagaRepo, outboxRepo, txManager)", v, name)},
+		{Point: "processors", Code: fmt.Sprintf("processors.%sSaga = %s", name, v)},
+		{Point: "workers", Code: fmt.Sprintf("g.Go(func() error {\n\t%s.WatchTimeouts(groupCtx)\n\treturn nil\n})", v)},
+	}
+
+	mainFile := filepath.Join(root, "cmd", "server", "main.go")
+	if _, err := helixAst.NewInjector(mainFile).Apply(injections); err != nil {
+		report.Warn(fmt.Sprintf("%s not wired: %v", relTo(root, mainFile), err))
+		for _, inj := range injections {
+			report.Pending(inj.Code)
+		}
+		return
+	}
+	report.Modified(mainFile)
+	report.Applied(fmt.Sprintf("%s built, set in worker.Processors and watching timeouts in %s", v, relTo(root, mainFile)))
+	report.Pending(fmt.Sprintf("start sagas with %s.Begin(txCtx, worker.%sData{...}) from a service: pass %s to its constructor", v, name, v))
+}
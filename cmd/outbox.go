@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/godamri/helix-cli/internal/outbox"
+	"github.com/godamri/helix-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// Connection flags shared by every outbox subcommand.
+var (
+	outboxURL    string
+	outboxToken  string
+	outboxBearer string
+	outboxDSN    string
+)
+
+// Filter flags.
+var (
+	outboxTopic  string
+	outboxStatus string
+	outboxFrom   string
+	outboxTo     string
+	outboxLimit  int
+	outboxYes    bool
+)
+
+var outboxCmd = &cobra.Command{
+	Use:   "outbox",
+	Short: "Inspect, replay and skip transactional outbox events",
+	Long: `Operates on a service's outbox through its operator API (--url, needs
+ADMIN_API_ENABLED=true and --token / HELIX_ADMIN_TOKEN) or directly in Postgres
+(--dsn / HELIX_OUTBOX_DSN), for when the service is down.
+
+Requeue resets FAILED, SKIPPED or PROCESSED events to PENDING with a fresh retry
+budget; skip marks PENDING or FAILED events SKIPPED so they are never published.`,
+	Example: `  helix-cli outbox list --url http://localhost:8080 --status FAILED
+  helix-cli outbox replay 6f1c2d3e-... --dsn "$DB_DSN"
+  helix-cli outbox requeue --topic order.created --status FAILED --from 2024-05-01T00:00:00Z`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var outboxListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "List outbox events, newest first",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true, // Remote failures aren't usage errors
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := outboxFilter(nil)
+		if err != nil {
+			return err
+		}
+		f.Limit = outboxLimit
+		return withOutbox(func(ctx context.Context, c outbox.Client) error {
+			events, err := c.List(ctx, f)
+			if err != nil {
+				return output.Wrap(output.CodeRemote, err)
+			}
+			report.Data = events
+
+			tw := tabwriter.NewWriter(humanOut(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "ID\tSTATUS\tTOPIC\tKEY\tRETRIES\tCREATED\tLAST ERROR")
+			for _, e := range events {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n", e.ID, e.Status, e.Topic, e.Key, e.RetryCount,
+					e.CreatedAt.Format(time.RFC3339), truncate(e.LastError, 60))
+			}
+			return tw.Flush()
+		})
+	},
+}
+
+var outboxShowCmd = &cobra.Command{
+	Use:          "show <id>",
+	Short:        "Show one outbox event with its payload and headers",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withOutbox(func(ctx context.Context, c outbox.Client) error {
+			e, err := c.Get(ctx, args[0])
+			if err != nil {
+				return output.Wrap(output.CodeRemote, err)
+			}
+			report.Data = e
+
+			b, _ := json.MarshalIndent(e, "", "  ")
+			printLine(string(b))
+			return nil
+		})
+	},
+}
+
+var outboxReplayCmd = &cobra.Command{
+	Use:          "replay <id>...",
+	Short:        "Put specific events back in the queue",
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runOutboxAction("Requeued", outbox.Filter{IDs: args}, outbox.Client.Requeue)
+	},
+}
+
+var outboxRequeueCmd = &cobra.Command{
+	Use:          "requeue [id...]",
+	Short:        "Bulk requeue events by topic, status, time range or ID",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := outboxBulkFilter(args, "Requeue")
+		if err != nil {
+			return err
+		}
+		return runOutboxAction("Requeued", f, outbox.Client.Requeue)
+	},
+}
+
+var outboxSkipCmd = &cobra.Command{
+	Use:          "skip [id...]",
+	Short:        "Mark events SKIPPED by ID or by topic, status and time range",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := outboxBulkFilter(args, "Skip")
+		if err != nil {
+			return err
+		}
+		return runOutboxAction("Skipped", f, outbox.Client.Skip)
+	},
+}
+
+func init() {
+	pf := outboxCmd.PersistentFlags()
+	pf.StringVar(&outboxURL, "url", os.Getenv("HELIX_ADMIN_URL"), "Service base URL for the operator API (env HELIX_ADMIN_URL)")
+	pf.StringVar(&outboxToken, "token", os.Getenv("HELIX_ADMIN_TOKEN"), "Operator token, sent as X-Admin-Token (env HELIX_ADMIN_TOKEN)")
+	pf.StringVar(&outboxBearer, "bearer", os.Getenv("HELIX_BEARER_TOKEN"), "JWT for services running with AUTH_ENABLED (env HELIX_BEARER_TOKEN)")
+	pf.StringVar(&outboxDSN, "dsn", os.Getenv("HELIX_OUTBOX_DSN"), "Postgres DSN; bypasses the API (env HELIX_OUTBOX_DSN)")
+
+	for _, c := range []*cobra.Command{outboxListCmd, outboxRequeueCmd, outboxSkipCmd} {
+		c.Flags().StringVar(&outboxTopic, "topic", "", "Only events of this topic")
+		c.Flags().StringVar(&outboxStatus, "status", "", "Only events in this status (PENDING, PROCESSING, PROCESSED, FAILED, SKIPPED)")
+		c.Flags().StringVar(&outboxFrom, "from", "", "Only events created at or after this time (RFC3339)")
+		c.Flags().StringVar(&outboxTo, "to", "", "Only events created before this time (RFC3339)")
+	}
+	outboxListCmd.Flags().IntVar(&outboxLimit, "limit", 100, "Maximum number of events (max 1000)")
+	for _, c := range []*cobra.Command{outboxRequeueCmd, outboxSkipCmd} {
+		c.Flags().BoolVarP(&outboxYes, "yes", "y", false, "Don't ask for confirmation of bulk actions")
+	}
+
+	outboxCmd.AddCommand(outboxListCmd)
+	outboxCmd.AddCommand(outboxShowCmd)
+	outboxCmd.AddCommand(outboxReplayCmd)
+	outboxCmd.AddCommand(outboxRequeueCmd)
+	outboxCmd.AddCommand(outboxSkipCmd)
+}
+
+// withOutbox connects to the selected backend and runs fn.
+func withOutbox(fn func(ctx context.Context, c outbox.Client) error) error {
+	ctx := context.Background()
+
+	var c outbox.Client
+	switch {
+	case outboxDSN != "":
+		db, err := outbox.OpenDB(ctx, outboxDSN)
+		if err != nil {
+			return output.Errorf(output.CodeRemote, "connect to database: %w", err)
+		}
+		c = db
+	case outboxURL != "":
+		if outboxToken == "" {
+			return output.Errorf(output.CodeInvalidArgument, "--token (or HELIX_ADMIN_TOKEN) is required with --url")
+		}
+		c = outbox.NewAPIClient(outboxURL, outboxToken, outboxBearer)
+	default:
+		return output.Errorf(output.CodeInvalidArgument, "set --url (operator API) or --dsn (direct database access)")
+	}
+	defer c.Close()
+
+	return fn(ctx, c)
+}
+
+func runOutboxAction(verb string, f outbox.Filter, action func(outbox.Client, context.Context, outbox.Filter) (int64, error)) error {
+	return withOutbox(func(ctx context.Context, c outbox.Client) error {
+		n, err := action(c, ctx, f)
+		if err != nil {
+			return output.Wrap(output.CodeRemote, err)
+		}
+		report.Data = map[string]int64{"affected": n}
+		report.Message(fmt.Sprintf("%s %d event(s)", verb, n))
+		printf("%s %d event(s).\n", verb, n)
+		return nil
+	})
+}
+
+// outboxBulkFilter builds the filter of a bulk action and confirms it unless
+// it only names IDs or --yes is set.
+func outboxBulkFilter(ids []string, action string) (outbox.Filter, error) {
+	f, err := outboxFilter(ids)
+	if err != nil {
+		return f, err
+	}
+	if f.Empty() {
+		return f, output.Errorf(output.CodeInvalidArgument, "%s needs IDs or at least one of --topic, --status, --from, --to", strings.ToLower(action))
+	}
+	if len(ids) > 0 && f.Topic == "" && f.Status == "" && f.From == nil && f.To == nil {
+		return f, nil
+	}
+	ok, err := askConfirm(outboxYes, &survey.Confirm{
+		Message: fmt.Sprintf("%s every event matching %s?", action, describeFilter(f)),
+	})
+	if err != nil {
+		return f, err
+	}
+	if !ok {
+		return f, output.Errorf(output.CodeInvalidArgument, "%s not confirmed (pass --yes)", strings.ToLower(action))
+	}
+	return f, nil
+}
+
+func outboxFilter(ids []string) (outbox.Filter, error) {
+	f := outbox.Filter{IDs: ids, Topic: outboxTopic, Status: strings.ToUpper(outboxStatus)}
+	for _, t := range []struct {
+		flag, value string
+		dest        **time.Time
+	}{{"from", outboxFrom, &f.From}, {"to", outboxTo, &f.To}} {
+		if t.value == "" {
+			continue
+		}
+		v, err := time.Parse(time.RFC3339, t.value)
+		if err != nil {
+			return f, output.Errorf(output.CodeInvalidArgument, "invalid --%s '%s' (expected RFC3339, e.g. 2024-05-01T00:00:00Z)", t.flag, t.value)
+		}
+		*t.dest = &v
+	}
+	return f, nil
+}
+
+func describeFilter(f outbox.Filter) string {
+	var parts []string
+	if len(f.IDs) > 0 {
+		parts = append(parts, fmt.Sprintf("%d id(s)", len(f.IDs)))
+	}
+	if f.Topic != "" {
+		parts = append(parts, "topic="+f.Topic)
+	}
+	if f.Status != "" {
+		parts = append(parts, "status="+f.Status)
+	}
+	if f.From != nil {
+		parts = append(parts, "from="+f.From.Format(time.RFC3339))
+	}
+	if f.To != nil {
+		parts = append(parts, "to="+f.To.Format(time.RFC3339))
+	}
+	return strings.Join(parts, " ")
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-3] + "..."
+}
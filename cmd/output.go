@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/godamri/helix-cli/internal/hooks"
+	"github.com/godamri/helix-cli/internal/manifest"
+	"github.com/godamri/helix-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// outputFormat is the value of the global --output flag (text | json).
+var outputFormat = "text"
+
+// report collects the structured result of the running command.
+// One command runs per process, so a package-level collector is enough.
+var report = output.NewResult()
+
+func jsonOutput() bool {
+	return outputFormat == "json"
+}
+
+// humanOut is where progress text goes: stdout normally, stderr in JSON mode
+// so stdout carries nothing but the result document.
+func humanOut() io.Writer {
+	if jsonOutput() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+func printf(format string, args ...interface{}) {
+	fmt.Fprintf(humanOut(), format, args...)
+}
+
+func printLine(args ...interface{}) {
+	fmt.Fprintln(humanOut(), args...)
+}
+
+// cliLogger logs to stderr; JSON-formatted when --output json is set.
+func cliLogger() *slog.Logger {
+	opts := &slog.HandlerOptions{Level: slog.LevelInfo}
+	if jsonOutput() {
+		return slog.New(slog.NewJSONHandler(os.Stderr, opts))
+	}
+	return slog.New(slog.NewTextHandler(os.Stderr, opts))
+}
+
+// newHookRunner builds a hook runner that honours --no-hooks and records outcomes in the report.
+func newHookRunner(pack *manifest.Pack, project *manifest.Project) *hooks.Runner {
+	r := hooks.NewRunner(pack, project, noHooks)
+	r.Out = humanOut()
+	r.OnResult = func(res hooks.Result) {
+		hr := output.HookResult{Phase: res.Phase, Name: res.Name, OK: res.Err == nil, Duration: res.Duration.String()}
+		if res.Err != nil {
+			hr.Error = res.Err.Error()
+		}
+		report.Hooks = append(report.Hooks, hr)
+	}
+	return r
+}
+
+// askSelect uses value when the flag was given, otherwise prompts.
+// In JSON mode prompting is impossible, so a missing value is an error.
+func askSelect(value *string, flag string, prompt *survey.Select) error {
+	if *value != "" {
+		for _, opt := range prompt.Options {
+			if opt == *value {
+				return nil
+			}
+		}
+		return output.Errorf(output.CodeInvalidArgument, "invalid --%s '%s' (expected one of: %s)", flag, *value, strings.Join(prompt.Options, ", "))
+	}
+	if jsonOutput() {
+		return output.Errorf(output.CodePromptRequired, "--%s is required with --output json", flag)
+	}
+	return survey.AskOne(prompt, value)
+}
+
+func askInput(value *string, what string, prompt *survey.Input) error {
+	if *value != "" {
+		return nil
+	}
+	if jsonOutput() {
+		return output.Errorf(output.CodePromptRequired, "%s is required with --output json", what)
+	}
+	return survey.AskOne(prompt, value)
+}
+
+// askConfirm returns assumeYes without prompting when --yes was given or in JSON mode.
+func askConfirm(assumeYes bool, prompt *survey.Confirm) (bool, error) {
+	if assumeYes || jsonOutput() {
+		return assumeYes || prompt.Default, nil
+	}
+	var confirm bool
+	err := survey.AskOne(prompt, &confirm)
+	return confirm, err
+}
+
+// errorCode maps an error to its stable output code.
+func errorCode(err error) string {
+	if code := output.CodeOf(err); code != "" {
+		return code
+	}
+
+	var hookErr *hooks.Error
+	switch {
+	case errors.As(err, &hookErr):
+		return output.CodeHookFailed
+	case errors.Is(err, manifest.ErrNoWorkspace), errors.Is(err, manifest.ErrNoProject), errors.Is(err, fs.ErrNotExist):
+		return output.CodeNotFound
+	case strings.HasPrefix(err.Error(), "unknown command"):
+		return output.CodeUnknownCommand
+	case strings.HasPrefix(err.Error(), "unknown flag"), strings.HasPrefix(err.Error(), "unknown shorthand flag"):
+		return output.CodeInvalidArgument
+	}
+	return output.CodeInternal
+}
+
+// wantsJSON pre-scans raw args so cobra can be silenced before flags are parsed.
+func wantsJSON(args []string) bool {
+	for i, a := range args {
+		switch {
+		case a == "--output=json", a == "-o=json", a == "-ojson":
+			return true
+		case (a == "--output" || a == "-o") && i+1 < len(args) && args[i+1] == "json":
+			return true
+		}
+	}
+	return false
+}
+
+// wrapArgValidators tags cobra's positional-argument errors as E_INVALID_ARGUMENT.
+func wrapArgValidators(c *cobra.Command) {
+	if c.Args != nil {
+		validate := c.Args
+		c.Args = func(cmd *cobra.Command, args []string) error {
+			return output.Wrap(output.CodeInvalidArgument, validate(cmd, args))
+		}
+	}
+	for _, child := range c.Commands() {
+		wrapArgValidators(child)
+	}
+}
+
+// finish prints the JSON result (in JSON mode) and sets the exit code.
+func finish(command string, err error) {
+	if !jsonOutput() {
+		cobra.CheckErr(err)
+		return
+	}
+
+	report.Command = command
+	if err != nil {
+		report.Fail(errorCode(err), err)
+	}
+	if werr := report.Write(os.Stdout); werr != nil {
+		fmt.Fprintln(os.Stderr, werr)
+	}
+	if err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/godamri/helix-cli/internal/hooks"
+	"github.com/godamri/helix-cli/internal/manifest"
+	"github.com/godamri/helix-cli/internal/output"
+	"github.com/godamri/helix-cli/internal/plugin"
+	helixTemplate "github.com/godamri/helix-cli/internal/template"
+	"github.com/spf13/cobra"
+)
+
+var pluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "Inspect external helix-cli-* plugins found on PATH",
+	Long: `Plugins are executables named helix-cli-<name> anywhere on PATH (git-style).
+
+  helix-cli foo ...           runs helix-cli-foo
+  helix-cli new foo-bar ...   runs helix-cli-new-foo-bar
+
+Built-in commands always win over plugins with the same name.
+See docs/PLUGINS.md for the JSON protocol.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var pluginListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List discovered plugins",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		plugins := plugin.Discover()
+		report.Data = append([]plugin.Plugin{}, plugins...)
+		if len(plugins) == 0 {
+			printf("No plugins found. Put a '%s<name>' executable on your PATH.\n", plugin.Prefix)
+			return nil
+		}
+		for _, p := range plugins {
+			printf("  %-28s %s\n", pluginCommandName(p.Name), p.Path)
+		}
+		return nil
+	},
+}
+
+func init() {
+	pluginCmd.AddCommand(pluginListCmd)
+}
+
+// pluginCommandName renders "new-feature-flag" as "new feature-flag" for display.
+func pluginCommandName(name string) string {
+	if rest, ok := strings.CutPrefix(name, "new-"); ok {
+		return "new " + rest
+	}
+	return name
+}
+
+// dispatchPlugin runs a plugin when args don't resolve to a built-in command.
+// It reports whether a plugin handled the invocation and under which command name.
+func dispatchPlugin(args []string) (bool, string, error) {
+	// Global flags may precede the plugin name: helix-cli --no-hooks new foo
+	flags := rootCmd.PersistentFlags()
+	i := 0
+	for i < len(args) && strings.HasPrefix(args[i], "-") {
+		name := strings.TrimLeft(strings.SplitN(args[i], "=", 2)[0], "-")
+		f := flags.Lookup(name)
+		if f == nil {
+			return false, "", nil // Let cobra report the unknown flag
+		}
+		if f.Value.Type() != "bool" && !strings.Contains(args[i], "=") {
+			i++
+		}
+		i++
+	}
+	if i >= len(args) {
+		return false, "", nil
+	}
+	leading, args := args[:i], args[i:]
+
+	var p *plugin.Plugin
+	var command string
+	var pluginArgs []string
+
+	found, rest, err := rootCmd.Find(args)
+	switch {
+	case err != nil:
+		// Unknown top-level command: helix-cli foo -> helix-cli-foo
+		p, _ = plugin.Lookup(args[0])
+		command, pluginArgs = args[0], args[1:]
+	case found.Name() == "new" && found.Parent() == rootCmd && len(rest) > 0 && !strings.HasPrefix(rest[0], "-"):
+		// Unknown generator: helix-cli new foo -> helix-cli-new-foo
+		p, _ = plugin.Lookup("new-" + rest[0])
+		command, pluginArgs = "new "+rest[0], rest[1:]
+	}
+	if p == nil {
+		return false, "", nil
+	}
+
+	if err := flags.Parse(leading); err != nil {
+		return true, command, output.Wrap(output.CodeInvalidArgument, err)
+	}
+	if err := applyOutputFormat(); err != nil {
+		return true, command, err
+	}
+	return true, command, runPlugin(p, command, pluginArgs)
+}
+
+func runPlugin(p *plugin.Plugin, command string, args []string) error {
+	logger := cliLogger()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get cwd: %w", err)
+	}
+
+	root := wd
+	project, err := manifest.FindProject(wd)
+	if err != nil && !errors.Is(err, manifest.ErrNoProject) {
+		return err
+	}
+
+	data := helixTemplate.TemplateData{GoModuleName: getGoModuleName(wd)}
+	if project != nil {
+		root = project.Root
+		data.ProjectName = project.Name
+		data.GoModuleName = project.Module
+		data.Driver = project.Driver
+		data.Broker = project.Broker
+	}
+	// Generators conventionally take the component name first, same as 'new entity'.
+	if strings.HasPrefix(command, "new ") && len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		data.EntityName = kebabToPascal(args[0])
+		data.EntityNameCamel = kebabToCamel(args[0])
+		data.EntityNameLower = strings.ToLower(strings.ReplaceAll(args[0], "-", ""))
+		data.EntityPluralLower = data.EntityNameLower + "s"
+	}
+
+	logger.Info("Running plugin", "plugin", p.Name, "path", p.Path)
+	resp, err := p.Run(plugin.Request{
+		ProtocolVersion: plugin.ProtocolVersion,
+		Command:         command,
+		Args:            args,
+		Cwd:             wd,
+		ProjectRoot:     root,
+		Manifest:        project,
+		TemplateData:    data,
+	})
+	if err != nil {
+		return output.Wrap(output.CodePlugin, err)
+	}
+
+	fetcher, err := templateFetcher()
+	if err != nil {
+		return err
+	}
+	gen := helixTemplate.NewGenerator(data, fetcher)
+
+	pack, err := manifest.LoadPack(fetcher)
+	if err != nil {
+		return err
+	}
+	hookRunner := newHookRunner(pack, project)
+
+	planned := make([]string, 0, len(resp.Files))
+	for _, f := range resp.Files {
+		planned = append(planned, filepath.Join(root, f.Dest))
+	}
+	hookCtx := hooks.Context{Command: command, Root: root, Files: hooks.RelativeFiles(root, planned)}
+
+	if err := hookRunner.Run(manifest.PhasePreRender, hookCtx); err != nil {
+		return err
+	}
+
+	res, err := plugin.Apply(resp, gen, root)
+	if res != nil {
+		for _, f := range res.Written {
+			printf("  created  %s\n", f)
+			report.Created(filepath.Join(root, f))
+		}
+		for _, f := range res.Skipped {
+			printf("  skipped  %s (already exists)\n", f)
+			report.Warn(fmt.Sprintf("skipped %s (already exists)", f))
+		}
+		if res.Injected > 0 {
+			printf("  wired    %d block(s) into %s\n", res.Injected, plugin.MainFile)
+			report.Modified(filepath.Join(root, plugin.MainFile))
+			report.Applied(fmt.Sprintf("%d block(s) injected into %s", res.Injected, plugin.MainFile))
+		}
+	}
+	if err != nil {
+		return output.Wrap(output.CodePlugin, err)
+	}
+
+	written := make([]string, 0, len(res.Written))
+	for _, f := range res.Written {
+		written = append(written, filepath.Join(root, f))
+	}
+	if project != nil && len(res.Written) > 0 {
+		recordPluginFiles(project, p, resp, res.Written)
+	}
+	hookCtx.Files = hooks.RelativeFiles(root, written)
+	if err := hookRunner.Run(manifest.PhasePostRender, hookCtx); err != nil {
+		return err
+	}
+	if err := hookRunner.Run(manifest.PhasePostWire, hookCtx); err != nil {
+		return err
+	}
+
+	for _, m := range resp.Messages {
+		printLine(m)
+		report.Message(m)
+	}
+	return nil
+}
+
+// recordPluginFiles notes plugin-rendered files in the project manifest.
+func recordPluginFiles(project *manifest.Project, p *plugin.Plugin, resp *plugin.Response, written []string) {
+	isWritten := make(map[string]bool, len(written))
+	for _, f := range written {
+		isWritten[f] = true
+	}
+	for _, f := range resp.Files {
+		if !isWritten[f.Dest] {
+			continue
+		}
+		tmpl := f.Source
+		if tmpl == "" {
+			tmpl = "(inline)"
+		}
+		project.RecordFile(f.Dest, tmpl, "plugin:"+p.Name)
+	}
+	if err := project.Save(); err != nil {
+		report.Warn(fmt.Sprintf("failed to update project manifest: %v", err))
+		return
+	}
+	report.Modified(filepath.Join(project.Root, manifest.ProjectFile))
+}
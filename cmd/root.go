@@ -2,7 +2,11 @@ package cmd
 
 import (
 	"io/fs"
+	"log/slog"
+	"os"
+	"strings"
 
+	"github.com/godamri/helix-cli/internal/output"
 	"github.com/spf13/cobra"
 )
 
@@ -10,26 +14,82 @@ import (
 // Injected from main.go
 var TemplateFS fs.FS
 
+// noHooks disables template-pack and project hooks for this invocation.
+var noHooks bool
+
+// templateDir is an explicit override directory, searched before all others.
+var templateDir string
+
 var rootCmd = &cobra.Command{
 	Use:   "helix-cli",
 	Short: "Helix Enterprise Microservice Generator",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return applyOutputFormat()
+	},
 }
 
 func Execute() {
-	cobra.CheckErr(rootCmd.Execute())
+	// Silence cobra before parsing so stdout carries nothing but the JSON result.
+	if wantsJSON(os.Args[1:]) {
+		outputFormat = "json"
+		rootCmd.SilenceErrors = true
+		rootCmd.SilenceUsage = true
+	}
+	slog.SetDefault(cliLogger())
+	wrapArgValidators(rootCmd)
+
+	// Built-ins first; anything unknown may be an external helix-cli-* plugin.
+	if handled, command, err := dispatchPlugin(os.Args[1:]); handled {
+		finish(command, err)
+		return
+	}
+
+	executed, err := rootCmd.ExecuteC()
+	command := ""
+	if executed != nil {
+		command = strings.TrimPrefix(strings.TrimPrefix(executed.CommandPath(), rootCmd.Name()), " ")
+	}
+	finish(command, err)
+}
+
+// applyOutputFormat validates --output and re-creates the default logger for it.
+func applyOutputFormat() error {
+	switch outputFormat {
+	case "text", "json":
+	default:
+		return output.Errorf(output.CodeInvalidArgument, "invalid --output '%s' (expected text or json)", outputFormat)
+	}
+	slog.SetDefault(cliLogger())
+	return nil
 }
 
 func init() {
+	rootCmd.PersistentFlags().BoolVar(&noHooks, "no-hooks", false, "Skip pre_render/post_render/post_wire hooks")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text", "Output format: text | json (JSON result on stdout, logs on stderr)")
+	rootCmd.PersistentFlags().StringVar(&templateDir, "template-dir", "", "Template override directory, searched before the project and ~/.helix/templates")
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return output.Wrap(output.CodeInvalidArgument, err)
+	})
+
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(updateTemplatesCmd)
+	rootCmd.AddCommand(workspaceCmd)
+	rootCmd.AddCommand(pluginCmd)
+	rootCmd.AddCommand(migrateCmd)
+	rootCmd.AddCommand(inspectCmd)
+	rootCmd.AddCommand(certsCmd)
+	rootCmd.AddCommand(templatesCmd)
+	rootCmd.AddCommand(outboxCmd)
+	rootCmd.AddCommand(dlqCmd)
+	rootCmd.AddCommand(eventsCmd)
+	rootCmd.AddCommand(docsCmd)
 
-	var newCmd = &cobra.Command{
-		Use:   "new",
-		Short: "Generate new components (consumer, entity, cache)",
-	}
 	newCmd.AddCommand(newEntityCmd)
 	newCmd.AddCommand(newConsumerCmd)
+	newCmd.AddCommand(newSagaCmd)
+	newCmd.AddCommand(newJobCmd)
 	newCmd.AddCommand(newCacheCmd) // Register Cache Command
+	newCmd.AddCommand(newOutboxPartitioningCmd)
 
 	rootCmd.AddCommand(newCmd)
 }
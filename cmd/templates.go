@@ -0,0 +1,403 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/godamri/helix-cli/internal/manifest"
+	"github.com/godamri/helix-cli/internal/output"
+	helixTemplate "github.com/godamri/helix-cli/internal/template"
+	"github.com/godamri/helix-cli/internal/verify"
+	"github.com/spf13/cobra"
+)
+
+var templatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "List, diff, eject and verify templates",
+	Long: `Each template is read from the first place that has it:
+  1. --template-dir <dir>
+  2. the current project's .helix/templates
+  3. ~/.helix/templates
+  4. the copy embedded in helix-cli
+Override directories mirror the embedded layout (templates/entity/dto.go.tmpl).
+These commands show which source wins and help you customize.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var (
+	verifyDrivers []string
+	verifyPack    string
+	verifyKeep    bool
+)
+
+var templatesVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Render every template combination and type-check the result",
+	Long: `Renders all templates for each driver, standalone and inside a workspace, together
+with a second entity ('new entity') and the consumer, cache and cached-repository generators.
+Each case is rendered into a temp module and type-checked offline with go/types:
+the standard library comes from GOROOT, a few third-party packages from built-in stubs,
+and anything else (helix-fnd, ent generated code, protoc output) is treated as opaque.
+
+Issues are reported as template:line, mapped back from the rendered file.
+Overrides apply like for every other command; use --pack to verify only a template pack
+directory on top of the embedded templates.`,
+	Example: `  helix-cli templates verify
+  helix-cli templates verify --driver pgx --keep
+  helix-cli templates verify --pack ./my-pack -o json`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true, // Failing verification isn't a usage error
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fetcher, err := templateFetcher()
+		if err != nil {
+			return err
+		}
+		if verifyPack != "" {
+			// A pack directory mirrors the embedded layout (templates/...).
+			if _, err := os.Stat(filepath.Join(verifyPack, "templates")); err != nil {
+				return output.Errorf(output.CodeNotFound, "'%s' is not a template pack (no templates/ directory)", verifyPack)
+			}
+			fetcher.Layers = []helixTemplate.Layer{{Source: helixTemplate.SourceTemplateDir, Dir: verifyPack}}
+		}
+
+		for _, d := range verifyDrivers {
+			if d != "ent" && d != "pgx" {
+				return output.Errorf(output.CodeInvalidArgument, "invalid --driver '%s' (expected ent or pgx)", d)
+			}
+		}
+
+		dir, err := os.MkdirTemp("", "helix-verify-")
+		if err != nil {
+			return output.Wrap(output.CodeIO, err)
+		}
+		if verifyKeep {
+			printf("Rendered cases kept in %s\n", dir)
+		} else {
+			defer os.RemoveAll(dir)
+		}
+
+		v := verify.New(fetcher, dir)
+		cases := verify.Matrix(verifyDrivers)
+		issues := []verify.Issue{}
+		for _, c := range cases {
+			slog.Info("Verifying", "case", c.Name)
+			found, err := v.Run(c)
+			if err != nil {
+				return output.Wrap(output.CodeIO, err)
+			}
+			issues = append(issues, found...)
+		}
+
+		report.Data = map[string]interface{}{"cases": cases, "issues": issues}
+		for _, issue := range issues {
+			printLine(issue.String())
+		}
+		if len(issues) > 0 {
+			return output.Errorf(output.CodeTemplate, "%d template issue(s) in %d case(s)", len(issues), len(cases))
+		}
+		printf("All templates OK (%d cases).\n", len(cases))
+		return nil
+	},
+}
+
+var templatesListOverridden bool
+
+// templateEntry is one row of 'templates list'.
+type templateEntry struct {
+	Path   string `json:"path"`
+	Source string `json:"source"`           // template-dir | project | user | embedded
+	Status string `json:"status,omitempty"` // identical | modified | local-only (for overrides)
+	File   string `json:"file,omitempty"`   // Override file on disk
+}
+
+var templatesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List templates and whether each comes from the embedded pack or a local override",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fetcher, err := templateFetcher()
+		if err != nil {
+			return err
+		}
+
+		entries := []templateEntry{}
+		embedded := map[string]bool{}
+		err = fetcher.Walk("templates", func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			embedded[path] = true
+			entry := templateEntry{Path: path}
+			entry.Source, entry.File = fetcher.Resolve(path)
+			if entry.File != "" {
+				entry.Status = "modified"
+				local, _ := os.ReadFile(entry.File)
+				if orig, _ := fetcher.Embedded.ReadFile(path); bytes.Equal(local, orig) {
+					entry.Status = "identical"
+				}
+			}
+			entries = append(entries, entry)
+			return nil
+		})
+		if err != nil {
+			return output.Wrap(output.CodeIO, err)
+		}
+
+		// Override files with no embedded counterpart (new files of a custom pack)
+		for _, layer := range fetcher.Layers {
+			filepath.WalkDir(filepath.Join(layer.Dir, "templates"), func(path string, d fs.DirEntry, err error) error {
+				if err != nil || d.IsDir() {
+					return nil
+				}
+				rel, _ := filepath.Rel(layer.Dir, path)
+				rel = filepath.ToSlash(rel)
+				if !embedded[rel] {
+					embedded[rel] = true // First layer wins, like ReadFile
+					entries = append(entries, templateEntry{Path: rel, Source: layer.Source, Status: "local-only", File: path})
+				}
+				return nil
+			})
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+
+		if templatesListOverridden {
+			local := entries[:0]
+			for _, e := range entries {
+				if e.Source != helixTemplate.SourceEmbedded {
+					local = append(local, e)
+				}
+			}
+			entries = local
+		}
+		report.Data = entries
+
+		tw := tabwriter.NewWriter(humanOut(), 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "SOURCE\tSTATUS\tTEMPLATE")
+		for _, e := range entries {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", e.Source, e.Status, e.Path)
+		}
+		return tw.Flush()
+	},
+}
+
+var templatesDiffCmd = &cobra.Command{
+	Use:   "diff [template]",
+	Short: "Show how local overrides differ from the embedded templates",
+	Long: `Prints a unified diff from the embedded template to its local override.
+Without an argument, diffs every overridden template.`,
+	Example: `  helix-cli templates diff entity/dto.go.tmpl
+  helix-cli templates diff`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fetcher, err := templateFetcher()
+		if err != nil {
+			return err
+		}
+
+		var paths []string
+		if len(args) > 0 {
+			path := templatePath(args[0])
+			if fetcher.Source(path) == helixTemplate.SourceEmbedded {
+				return output.Errorf(output.CodeNotFound, "no override of '%s' in --template-dir, project or ~/.helix/templates", path)
+			}
+			paths = append(paths, path)
+		} else {
+			fetcher.Walk("templates", func(path string, d fs.DirEntry, err error) error {
+				if err == nil && !d.IsDir() && fetcher.Source(path) != helixTemplate.SourceEmbedded {
+					paths = append(paths, path)
+				}
+				return err
+			})
+		}
+
+		type fileDiff struct {
+			Path   string `json:"path"`
+			Source string `json:"source"`
+			File   string `json:"file"`
+			Diff   string `json:"diff"` // Empty when identical
+		}
+		diffs := []fileDiff{}
+		for _, path := range paths {
+			source, file := fetcher.Resolve(path)
+			local, err := os.ReadFile(file)
+			if err != nil {
+				return output.Wrap(output.CodeIO, err)
+			}
+			orig, err := fetcher.Embedded.ReadFile(path)
+			if err != nil {
+				return output.Errorf(output.CodeNotFound, "'%s' is not an embedded template: %w", path, err)
+			}
+			d := helixTemplate.Diff("embedded/"+path, file, orig, local)
+			diffs = append(diffs, fileDiff{Path: path, Source: source, File: file, Diff: d})
+
+			if d == "" {
+				printf("%s: %s override identical to embedded\n", path, source)
+				continue
+			}
+			printf("%s", d)
+		}
+		report.Data = diffs
+		if len(paths) == 0 {
+			printLine("No local overrides.")
+		}
+		return nil
+	},
+}
+
+var (
+	ejectProject bool
+	ejectForce   bool
+)
+
+var templatesEjectCmd = &cobra.Command{
+	Use:   "eject <template|dir>...",
+	Short: "Copy embedded templates into the override directory for customization",
+	Long: `Copies embedded templates to ~/.helix/templates, or with --project to the current
+project's .helix/templates. A directory ejects every template under it.`,
+	Example: `  helix-cli templates eject entity/handler_impl.go.tmpl
+  helix-cli templates eject entity --project`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fetcher, err := templateFetcher()
+		if err != nil {
+			return err
+		}
+
+		destRoot := helixTemplate.UserDir()
+		if ejectProject {
+			project, err := manifest.FindProject(".")
+			if err != nil {
+				return output.Wrap(output.CodeNotFound, err)
+			}
+			destRoot = filepath.Join(project.Root, manifest.ProjectPackDir)
+		}
+
+		var paths []string
+		for _, arg := range args {
+			root := templatePath(arg)
+			err := fetcher.Walk(root, func(path string, d fs.DirEntry, err error) error {
+				if err == nil && !d.IsDir() {
+					paths = append(paths, path)
+				}
+				return err
+			})
+			if err != nil {
+				return output.Errorf(output.CodeNotFound, "'%s' is not an embedded template or directory", root)
+			}
+		}
+
+		for _, path := range paths {
+			dest := filepath.Join(destRoot, filepath.FromSlash(path))
+			if _, err := os.Stat(dest); err == nil && !ejectForce {
+				return output.Errorf(output.CodeAlreadyExists, "'%s' already exists (use --force to overwrite)", dest)
+			}
+		}
+		for _, path := range paths {
+			content, err := fetcher.Embedded.ReadFile(path)
+			if err != nil {
+				return output.Wrap(output.CodeIO, err)
+			}
+			dest := filepath.Join(destRoot, filepath.FromSlash(path))
+			if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+				return output.Wrap(output.CodeIO, err)
+			}
+			if err := os.WriteFile(dest, content, 0644); err != nil {
+				return output.Wrap(output.CodeIO, err)
+			}
+			report.Created(dest)
+			printf("Ejected %s -> %s\n", path, dest)
+		}
+		report.Next("Edit the ejected files; 'helix-cli templates diff' shows your changes")
+		return nil
+	},
+}
+
+// templateFetcher is the fetcher every generator uses. Lookup order:
+// --template-dir, the current project's .helix/templates, ~/.helix/templates, embedded.
+func templateFetcher() (*helixTemplate.SmartFetcher, error) {
+	if TemplateFS == nil {
+		return nil, fmt.Errorf("embedded template FS is nil")
+	}
+	fetcher := helixTemplate.NewSmartFetcher(TemplateFS, cliLogger())
+
+	project, err := manifest.FindProject(".")
+	switch {
+	case err == nil:
+		fetcher.Prepend(helixTemplate.SourceProject, filepath.Join(project.Root, manifest.ProjectPackDir))
+	case !errors.Is(err, manifest.ErrNoProject):
+		return nil, err
+	}
+
+	if templateDir != "" {
+		abs, err := filepath.Abs(templateDir)
+		if err != nil {
+			return nil, err
+		}
+		if info, err := os.Stat(abs); err != nil || !info.IsDir() {
+			return nil, output.Errorf(output.CodeNotFound, "--template-dir '%s' is not a directory", templateDir)
+		}
+		fetcher.Prepend(helixTemplate.SourceTemplateDir, abs)
+	}
+	return fetcher, nil
+}
+
+// recordSources notes in the project manifest which template and source rendered each file.
+func recordSources(project *manifest.Project, fetcher *helixTemplate.SmartFetcher, files map[string]string) {
+	for src, dest := range files {
+		if strings.HasSuffix(src, ".keep") {
+			continue // Written empty, not rendered
+		}
+		if abs, err := filepath.Abs(dest); err == nil {
+			project.RecordFile(abs, src, fetcher.Source(src))
+		}
+	}
+}
+
+// templatePath normalizes a user-supplied template path: "entity/dto.go.tmpl",
+// "./templates/entity/dto.go.tmpl" and "templates/entity/dto.go.tmpl" are the same.
+func templatePath(arg string) string {
+	p := path.Clean(filepath.ToSlash(arg))
+	if p != "templates" && !strings.HasPrefix(p, "templates/") {
+		p = "templates/" + p
+	}
+	return p
+}
+
+func init() {
+	templatesListCmd.Flags().BoolVar(&templatesListOverridden, "overridden", false, "Only list locally overridden templates")
+	templatesEjectCmd.Flags().BoolVar(&ejectProject, "project", false, "Eject into the project's .helix/templates instead of ~/.helix/templates")
+	templatesEjectCmd.Flags().BoolVar(&ejectForce, "force", false, "Overwrite existing overrides")
+	templatesCmd.AddCommand(templatesListCmd)
+	templatesCmd.AddCommand(templatesDiffCmd)
+	templatesCmd.AddCommand(templatesEjectCmd)
+
+	templatesVerifyCmd.Flags().StringSliceVar(&verifyDrivers, "driver", []string{"ent", "pgx"}, "Drivers to verify")
+	templatesVerifyCmd.Flags().StringVar(&verifyPack, "pack", "", "Template pack directory to verify instead of the override chain")
+	templatesVerifyCmd.Flags().BoolVar(&verifyKeep, "keep", false, "Keep the rendered cases for inspection")
+	templatesCmd.AddCommand(templatesVerifyCmd)
+}
+
+// recordGenerated records a single-file generator's output in the enclosing project, if any.
+func recordGenerated(fetcher *helixTemplate.SmartFetcher, src, dest string) {
+	project, err := manifest.FindProject(filepath.Dir(dest))
+	if err != nil {
+		return // Not inside a helix project
+	}
+	recordSources(project, fetcher, map[string]string{src: dest})
+	if err := project.Save(); err != nil {
+		report.Warn(fmt.Sprintf("failed to update project manifest: %v", err))
+		return
+	}
+	report.Modified(filepath.Join(project.Root, manifest.ProjectFile))
+}
@@ -2,11 +2,12 @@ package cmd
 
 import (
 	"fmt"
-	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
 
+	"github.com/godamri/helix-cli/internal/output"
+	helixTemplate "github.com/godamri/helix-cli/internal/template"
 	"github.com/spf13/cobra"
 )
 
@@ -21,14 +22,9 @@ Default Repository: %s`, DefaultTemplateRepo),
 	Example: `  helix-cli update-templates (uses default official repo)
   helix-cli update-templates https://github.com/my-org/custom-templates.git (uses custom fork)`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Setup Path
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return fmt.Errorf("failed to get home dir: %w", err)
-		}
-		localTemplateDir := filepath.Join(home, ".helix", "templates")
+		localTemplateDir := helixTemplate.UserDir()
 
-		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+		logger := cliLogger()
 
 		// Check if directory exists
 		if _, err := os.Stat(localTemplateDir); os.IsNotExist(err) {
@@ -46,12 +42,13 @@ Default Repository: %s`, DefaultTemplateRepo),
 			}
 
 			c := exec.Command("git", "clone", repoURL, localTemplateDir)
-			c.Stdout = os.Stdout
+			c.Stdout = humanOut()
 			c.Stderr = os.Stderr
 			if err := c.Run(); err != nil {
-				return fmt.Errorf("git clone failed: %w", err)
+				return output.Errorf(output.CodeCommandFailed, "git clone failed: %w", err)
 			}
-			fmt.Println("Templates installed successfully.")
+			printLine("Templates installed successfully.")
+			report.Created(localTemplateDir)
 
 		} else {
 			// --- UPDATE ---
@@ -60,16 +57,17 @@ Default Repository: %s`, DefaultTemplateRepo),
 			// We trust 'git pull' to use the origin configured in .git/config
 			c := exec.Command("git", "pull")
 			c.Dir = localTemplateDir
-			c.Stdout = os.Stdout
+			c.Stdout = humanOut()
 			c.Stderr = os.Stderr
 			if err := c.Run(); err != nil {
 				// Fallback diagnostic
 				if _, err := os.Stat(filepath.Join(localTemplateDir, ".git")); os.IsNotExist(err) {
 					return fmt.Errorf("directory exists but is not a git repository. Remove it manually: rm -rf %s", localTemplateDir)
 				}
-				return fmt.Errorf("git pull failed: %w", err)
+				return output.Errorf(output.CodeCommandFailed, "git pull failed: %w", err)
 			}
-			fmt.Println("Templates updated successfully.")
+			printLine("Templates updated successfully.")
+			report.Modified(localTemplateDir)
 		}
 
 		return nil
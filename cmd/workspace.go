@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/godamri/helix-cli/internal/manifest"
+	"github.com/godamri/helix-cli/internal/output"
+	"github.com/godamri/helix-cli/internal/scaffold"
+	helixTemplate "github.com/godamri/helix-cli/internal/template"
+	"github.com/spf13/cobra"
+)
+
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Manage a go.work based monorepo of Helix services",
+	Long: `A workspace holds several Helix services side by side with ONE shared
+docker-compose.infra.yml, one go.work and a shared api/proto directory.
+
+Run 'helix-cli init' from the workspace root to add services to it.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var workspaceInitCmd = &cobra.Command{
+	Use:     "init [name]",
+	Short:   "Create a new Helix workspace (monorepo)",
+	Example: "  helix-cli workspace init platform-orders",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		root := filepath.Join(".", name)
+		if _, err := os.Stat(root); err == nil {
+			return output.Errorf(output.CodeAlreadyExists, "directory '%s' already exists", root)
+		}
+
+		fetcher, err := templateFetcher()
+		if err != nil {
+			return err
+		}
+		gen := helixTemplate.NewGenerator(helixTemplate.TemplateData{ProjectName: name}, fetcher)
+
+		files := scaffold.WorkspaceFiles(root)
+		for src, dest := range files {
+			if err := gen.ProcessFile(src, dest); err != nil {
+				os.RemoveAll(root)
+				return output.Errorf(output.CodeTemplate, "TEMPLATE ERROR: %w", err)
+			}
+			report.Created(dest)
+		}
+
+		absRoot, err := filepath.Abs(root)
+		if err != nil {
+			return err
+		}
+		ws := &manifest.Workspace{Name: name, Services: []manifest.Service{}, Root: absRoot}
+		if err := ws.Save(); err != nil {
+			os.RemoveAll(root)
+			return output.Errorf(output.CodeIO, "write workspace manifest: %w", err)
+		}
+		report.Created(filepath.Join(root, manifest.WorkspaceFile))
+
+		if err := runShellCommand(root, "go", "work", "init"); err != nil {
+			os.RemoveAll(root)
+			return err
+		}
+		report.Created(filepath.Join(root, "go.work"))
+		runOptionalCommand(root, "git", "init", "-b", "main")
+
+		printf("\nWorkspace %s created.\n", name)
+		printf("Next: cd %s && helix-cli init svc-<name>\n", name)
+		report.Next(fmt.Sprintf("cd %s && helix-cli init svc-<name>", name))
+		return nil
+	},
+}
+
+var workspaceListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List services registered in the current workspace",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ws, err := manifest.FindWorkspace(".")
+		if err != nil {
+			return err
+		}
+
+		report.Data = ws
+		printf("Workspace: %s (%s)\n", ws.Name, ws.Root)
+		if len(ws.Services) == 0 {
+			printLine("No services registered yet. Run 'helix-cli init' from the workspace root.")
+			return nil
+		}
+		for _, svc := range ws.Services {
+			printf("  %-24s %-6s %s\n", svc.Name, svc.Driver, svc.Path)
+		}
+		return nil
+	},
+}
+
+var workspaceRunCmd = &cobra.Command{
+	Use:     "run [make-target]",
+	Short:   "Run a make target in every service",
+	Example: "  helix-cli workspace run migrate-status",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAcrossServices("make", args[0])
+	},
+}
+
+var workspaceExecCmd = &cobra.Command{
+	Use:     "exec -- [command...]",
+	Short:   "Run an arbitrary command in every service directory",
+	Example: "  helix-cli workspace exec -- go vet ./...",
+	Args:    cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAcrossServices(args[0], args[1:]...)
+	},
+}
+
+var workspaceTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Run 'go test ./...' in every service",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAcrossServices("go", "test", "./...")
+	},
+}
+
+var workspaceMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Apply pending migrations (make migrate-apply) in every service",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAcrossServices("make", "migrate-apply")
+	},
+}
+
+func init() {
+	workspaceCmd.AddCommand(workspaceInitCmd)
+	workspaceCmd.AddCommand(workspaceListCmd)
+	workspaceCmd.AddCommand(workspaceRunCmd)
+	workspaceCmd.AddCommand(workspaceExecCmd)
+	workspaceCmd.AddCommand(workspaceTestCmd)
+	workspaceCmd.AddCommand(workspaceMigrateCmd)
+}
+
+// registerWorkspaceService adds a freshly generated service to go.work and the workspace manifest.
+func registerWorkspaceService(ws *manifest.Workspace, serviceDir string, data helixTemplate.TemplateData) error {
+	absDir, err := filepath.Abs(serviceDir)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(ws.Root, absDir)
+	if err != nil {
+		return fmt.Errorf("resolve service path: %w", err)
+	}
+	rel = filepath.ToSlash(rel)
+
+	if err := runShellCommand(ws.Root, "go", "work", "use", "./"+rel); err != nil {
+		slog.Warn("go work use failed, add the service to go.work manually", "path", rel, "error", err)
+		report.Warn(fmt.Sprintf("go work use failed, add ./%s to go.work manually", rel))
+	} else {
+		report.Modified(filepath.Join(ws.Root, "go.work"))
+	}
+
+	ws.AddService(manifest.Service{
+		Name:   data.ProjectName,
+		Path:   rel,
+		Module: data.GoModuleName,
+		Driver: data.Driver,
+	})
+	if err := ws.Save(); err != nil {
+		return output.Errorf(output.CodeIO, "update workspace manifest: %w", err)
+	}
+	report.Modified(filepath.Join(ws.Root, manifest.WorkspaceFile))
+
+	slog.Info("Service registered in workspace", "workspace", ws.Name, "path", rel)
+	return nil
+}
+
+// runAcrossServices executes the command in each service directory sequentially.
+// It keeps going after a failure so a single broken service doesn't hide the others.
+func runAcrossServices(name string, args ...string) error {
+	ws, err := manifest.FindWorkspace(".")
+	if err != nil {
+		return err
+	}
+	if len(ws.Services) == 0 {
+		return output.Errorf(output.CodeNotFound, "workspace '%s' has no registered services", ws.Name)
+	}
+
+	type serviceRun struct {
+		Service string `json:"service"`
+		OK      bool   `json:"ok"`
+		Error   string `json:"error,omitempty"`
+	}
+	runs := make([]serviceRun, 0, len(ws.Services))
+	report.Data = &runs
+
+	var failed []string
+	for _, svc := range ws.Services {
+		printf("\n==> [%s] %s %s\n", svc.Name, name, strings.Join(args, " "))
+
+		c := exec.Command(name, args...)
+		c.Dir = filepath.Join(ws.Root, svc.Path)
+		c.Stdout = humanOut()
+		c.Stderr = os.Stderr
+		run := serviceRun{Service: svc.Name, OK: true}
+		if err := c.Run(); err != nil {
+			slog.Error("Service command failed", "service", svc.Name, "error", err)
+			failed = append(failed, svc.Name)
+			run.OK, run.Error = false, err.Error()
+		}
+		runs = append(runs, run)
+	}
+
+	if len(failed) > 0 {
+		return output.Errorf(output.CodeCommandFailed, "command failed in %d/%d services: %s", len(failed), len(ws.Services), strings.Join(failed, ", "))
+	}
+	printf("\nAll %d services OK.\n", len(ws.Services))
+	return nil
+}
@@ -2,8 +2,31 @@ package ast
 
 import (
 	"fmt"
+	goast "go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"strconv"
+	"strings"
 )
 
+// MarkerPrefix identifies injection points in generated Go files, e.g. "// helix:inject:wiring".
+const MarkerPrefix = "// helix:inject:"
+
+// Import is a single import spec to add alongside an injection.
+type Import struct {
+	Alias string `json:"alias,omitempty"`
+	Path  string `json:"path"`
+}
+
+// Injection is a block of code inserted right above a marker comment.
+type Injection struct {
+	Point   string   `json:"point"`
+	Code    string   `json:"code"`
+	Imports []Import `json:"imports,omitempty"`
+}
+
 type Injector struct {
 	FilePath string
 }
@@ -12,6 +35,114 @@ func NewInjector(filePath string) *Injector {
 	return &Injector{FilePath: filePath}
 }
 
+// Apply inserts every injection into the file and adds missing imports.
+// The result must parse and gofmt cleanly, otherwise nothing is written.
+// Blocks that are already present are skipped, so re-running is safe.
+func (i *Injector) Apply(injections []Injection) (int, error) {
+	src, err := os.ReadFile(i.FilePath)
+	if err != nil {
+		return 0, fmt.Errorf("read %s: %w", i.FilePath, err)
+	}
+	out := string(src)
+
+	applied := 0
+	for _, inj := range injections {
+		code := strings.TrimSpace(inj.Code)
+		if code != "" && !containsBlock(out, code) {
+			out, err = insertAtMarker(out, inj.Point, code)
+			if err != nil {
+				return 0, err
+			}
+			applied++
+		}
+
+		for _, imp := range inj.Imports {
+			out, err = addImport(out, imp)
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	formatted, err := format.Source([]byte(out))
+	if err != nil {
+		return 0, fmt.Errorf("injection produced invalid Go in %s: %w", i.FilePath, err)
+	}
+	if string(formatted) == string(src) {
+		return applied, nil
+	}
+	return applied, os.WriteFile(i.FilePath, formatted, 0644)
+}
+
+// containsBlock reports whether code already appears in src, ignoring indentation.
+func containsBlock(src, code string) bool {
+	return strings.Contains(normalize(src), normalize(code))
+}
+
+func normalize(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSpace(l)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func insertAtMarker(src, point, code string) (string, error) {
+	marker := MarkerPrefix + point
+	lines := strings.Split(src, "\n")
+
+	for idx, line := range lines {
+		if strings.TrimSpace(line) != marker {
+			continue
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+
+		var block []string
+		for _, c := range strings.Split(code, "\n") {
+			block = append(block, indent+c)
+		}
+
+		result := append([]string{}, lines[:idx]...)
+		result = append(result, block...)
+		result = append(result, lines[idx:]...)
+		return strings.Join(result, "\n"), nil
+	}
+
+	return "", fmt.Errorf("injection point %q not found (expected a '%s' comment)", point, marker)
+}
+
+func addImport(src string, imp Import) (string, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, parser.ImportsOnly)
+	if err != nil {
+		return "", fmt.Errorf("parse imports: %w", err)
+	}
+
+	for _, spec := range f.Imports {
+		if p, _ := strconv.Unquote(spec.Path.Value); p == imp.Path {
+			return src, nil
+		}
+	}
+
+	line := strconv.Quote(imp.Path)
+	if imp.Alias != "" {
+		line = imp.Alias + " " + line
+	}
+
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*goast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT || !gen.Rparen.IsValid() {
+			continue
+		}
+		off := fset.Position(gen.Rparen).Offset
+		return src[:off] + "\t" + line + "\n" + src[off:], nil
+	}
+
+	// No grouped import block: add one after the package clause.
+	off := fset.Position(f.Name.End()).Offset
+	return src[:off] + "\n\nimport " + line + "\n" + src[off:], nil
+}
+
 func (i *Injector) InjectEntityWiring(entityName, entityCamel string) error {
 	fmt.Println("\nMANUAL WIRING REQUIRED")
 	fmt.Println("---------------------------------------------------------")
@@ -0,0 +1,298 @@
+// Package asyncapi describes the messaging side of a service as an AsyncAPI 3
+// document: the topics it publishes through the outbox, the topics its
+// consumers subscribe to, and the payload schemas of its event contracts.
+package asyncapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/godamri/helix-cli/internal/events"
+	"github.com/godamri/helix-cli/internal/inspect"
+)
+
+// Version is the AsyncAPI specification version of generated documents.
+const Version = "3.0.0"
+
+type Document struct {
+	AsyncAPI           string                `json:"asyncapi"`
+	Info               Info                  `json:"info"`
+	DefaultContentType string                `json:"defaultContentType"`
+	Servers            map[string]*Server    `json:"servers,omitempty"`
+	Channels           map[string]*Channel   `json:"channels"`
+	Operations         map[string]*Operation `json:"operations"`
+	Components         Components            `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Server struct {
+	Host        string               `json:"host"`
+	Protocol    string               `json:"protocol"`
+	Description string               `json:"description,omitempty"`
+	Variables   map[string]*Variable `json:"variables,omitempty"`
+}
+
+type Variable struct {
+	Default     string `json:"default,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type Channel struct {
+	Address     string         `json:"address"`
+	Description string         `json:"description,omitempty"`
+	Messages    map[string]Ref `json:"messages,omitempty"`
+	Servers     []Ref          `json:"servers,omitempty"`
+}
+
+type Operation struct {
+	Action      string `json:"action"` // send | receive
+	Channel     Ref    `json:"channel"`
+	Summary     string `json:"summary,omitempty"`
+	Description string `json:"description,omitempty"`
+	Messages    []Ref  `json:"messages,omitempty"`
+}
+
+type Components struct {
+	Messages map[string]*Message `json:"messages,omitempty"`
+}
+
+type Message struct {
+	Name        string         `json:"name"`
+	Title       string         `json:"title,omitempty"`
+	Summary     string         `json:"summary,omitempty"`
+	ContentType string         `json:"contentType,omitempty"`
+	Payload     *events.Schema `json:"payload,omitempty"`
+}
+
+// Ref is a JSON reference to another part of the document.
+type Ref struct {
+	Ref string `json:"$ref"`
+}
+
+// Options are the document metadata not found in the source.
+type Options struct {
+	Version string // info.version
+	Broker  string // EVENT_BROKER of the project; "" or "log" adds no outbox server
+}
+
+// brokerServers maps EVENT_BROKER names to their AsyncAPI protocol, the local
+// default address and the variable that configures it.
+var brokerServers = map[string]struct{ protocol, host, env string }{
+	"kafka":    {"kafka", "localhost:9092", "KAFKA_BROKERS"},
+	"nats":     {"nats", "localhost:4222", "NATS_URL"},
+	"rabbitmq": {"amqp", "localhost:5672", "RABBITMQ_URL"},
+	"redis":    {"redis", "localhost:6379", "REDIS_ADDR"},
+	"webhook":  {"https", "localhost", "WEBHOOK_URL"},
+}
+
+// Build assembles the document of the service described by r. Every contract
+// is a published message, whether or not a publishEvent site was found for it.
+func Build(r *inspect.Report, contracts []events.Contract, opts Options) *Document {
+	doc := &Document{
+		AsyncAPI: Version,
+		Info: Info{
+			Title:   r.Service,
+			Version: opts.Version,
+			Description: fmt.Sprintf("Events published (transactional outbox) and consumed by %s. "+
+				"With CLOUDEVENTS_MODE=structured, the default, each payload is the `data` of a CloudEvents envelope. "+
+				"Generated by 'helix-cli docs asyncapi'; do not edit.", r.Service),
+		},
+		DefaultContentType: "application/json",
+		Servers:            map[string]*Server{},
+		Channels:           map[string]*Channel{},
+		Operations:         map[string]*Operation{},
+		Components:         Components{Messages: map[string]*Message{}},
+	}
+
+	outboxServer := addServer(doc, opts.Broker)
+
+	byTopic := map[string][]string{} // topic -> message names
+	for _, c := range contracts {
+		payload := *c.Schema
+		payload.Draft, payload.ID = "", ""
+		doc.Components.Messages[c.Name] = &Message{
+			Name:        c.Name,
+			Title:       fmt.Sprintf("%s v%d", c.Topic, c.Version),
+			Summary:     fmt.Sprintf("Schema %s", c.Path()),
+			ContentType: "application/json",
+			Payload:     &payload,
+		}
+		byTopic[c.Topic] = append(byTopic[c.Topic], c.Name)
+	}
+
+	// Sites are files, not lines, so the document only changes with the API.
+	published := map[string][]string{} // topic -> files publishing it
+	for _, c := range contracts {
+		published[c.Topic] = published[c.Topic]
+	}
+	for _, p := range r.Publishes {
+		if file := sourceFile(p.Pos); !contains(published[p.Topic], file) {
+			published[p.Topic] = append(published[p.Topic], file)
+		}
+	}
+	for _, topic := range sortedKeys(published) {
+		id := addChannel(doc, topic, byTopic[topic], outboxServer)
+		op := &Operation{
+			Action:   "send",
+			Channel:  Ref{"#/channels/" + id},
+			Summary:  "Published through the transactional outbox",
+			Messages: channelMessages(id, byTopic[topic]),
+		}
+		if sites := published[topic]; len(sites) > 0 {
+			op.Description = "Published in " + strings.Join(sites, ", ")
+		}
+		doc.Operations["publish-"+id] = op
+	}
+
+	if len(r.Consumers) > 0 {
+		// Consumers read from Kafka whatever the outbox broker is.
+		consumerServer := addServer(doc, "kafka")
+		for _, c := range r.Consumers {
+			id := addChannel(doc, c.Topic, byTopic[c.Topic], consumerServer)
+			op := &Operation{
+				Action:   "receive",
+				Channel:  Ref{"#/channels/" + id},
+				Summary:  "Consumed in " + sourceFile(c.Pos),
+				Messages: channelMessages(id, byTopic[c.Topic]),
+			}
+			group := c.GroupID
+			if c.Name != "" {
+				op.Summary = "Consumed by " + c.Name + " (" + sourceFile(c.Pos) + ")"
+				if group == "" {
+					group = r.Service + "-" + c.Name // Default of worker.Consumers
+				}
+			}
+			var notes []string
+			if group != "" {
+				notes = append(notes, "Consumer group: `"+group+"`.")
+			}
+			if c.MaxRetries != "" {
+				notes = append(notes, "Retried "+c.MaxRetries+" times.")
+			}
+			if c.DLQTopic != "" {
+				notes = append(notes, "Messages that still fail go to "+c.DLQTopic+".")
+			}
+			op.Description = strings.Join(notes, " ")
+			doc.Operations[operationID(doc, "consume-"+id)] = op
+
+			if c.DLQTopic != "" {
+				dlq := addChannel(doc, c.DLQTopic, byTopic[c.Topic], consumerServer)
+				doc.Channels[dlq].Description = "Dead letters of " + c.Topic + ", with the original payload."
+				doc.Operations["deadletter-"+dlq] = &Operation{
+					Action:   "send",
+					Channel:  Ref{"#/channels/" + dlq},
+					Summary:  "Messages of " + c.Topic + " the consumer gave up on",
+					Messages: channelMessages(dlq, byTopic[c.Topic]),
+				}
+			}
+		}
+	}
+	return doc
+}
+
+// Render returns the document as indented JSON.
+func (d *Document) Render() ([]byte, error) {
+	b, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+func addServer(doc *Document, broker string) []Ref {
+	s, ok := brokerServers[broker]
+	if !ok {
+		return nil
+	}
+	if _, exists := doc.Servers[broker]; !exists {
+		doc.Servers[broker] = &Server{
+			Host:     "{host}",
+			Protocol: s.protocol,
+			Variables: map[string]*Variable{
+				"host": {Default: s.host, Description: "Set with " + s.env},
+			},
+		}
+	}
+	return []Ref{{"#/servers/" + broker}}
+}
+
+var invalidKey = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// addChannel returns the channel ID of topic, creating the channel on first use.
+func addChannel(doc *Document, topic string, messages []string, servers []Ref) string {
+	id := invalidKey.ReplaceAllString(topic, "_")
+	ch, ok := doc.Channels[id]
+	if !ok {
+		ch = &Channel{Address: topic, Messages: map[string]Ref{}}
+		doc.Channels[id] = ch
+	}
+	for _, name := range messages {
+		ch.Messages[name] = Ref{"#/components/messages/" + name}
+	}
+	for _, s := range servers {
+		if !hasRef(ch.Servers, s) {
+			ch.Servers = append(ch.Servers, s)
+		}
+	}
+	return id
+}
+
+func channelMessages(channel string, names []string) []Ref {
+	var refs []Ref
+	for _, name := range names {
+		refs = append(refs, Ref{"#/channels/" + channel + "/messages/" + name})
+	}
+	return refs
+}
+
+// operationID is key, numbered when several consumers share a topic.
+func operationID(doc *Document, key string) string {
+	id := key
+	for n := 2; doc.Operations[id] != nil; n++ {
+		id = fmt.Sprintf("%s-%d", key, n)
+	}
+	return id
+}
+
+// sourceFile drops the line from an inspect position.
+func sourceFile(pos string) string {
+	if i := strings.LastIndex(pos, ":"); i > 0 {
+		return pos[:i]
+	}
+	return pos
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+func hasRef(refs []Ref, r Ref) bool {
+	for _, x := range refs {
+		if x == r {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
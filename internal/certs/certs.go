@@ -0,0 +1,304 @@
+package certs
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// File names inside the certs directory. They match what .env points at.
+const (
+	CACert     = "ca.crt"
+	CAKey      = "ca.key"
+	ServerCert = "server.crt"
+	ServerKey  = "server.key"
+	ClientCert = "client.crt"
+	ClientKey  = "client.key"
+)
+
+const organization = "Helix Dev"
+
+// ErrNoCA is returned when the directory has no CA yet.
+var ErrNoCA = errors.New("no CA found (run 'helix-cli certs init' first)")
+
+// Authority is a local development CA.
+type Authority struct {
+	Cert *x509.Certificate
+	Key  crypto.Signer
+}
+
+// Request describes a leaf certificate to issue.
+type Request struct {
+	CommonName string
+	DNSNames   []string
+	IPs        []net.IP
+	Client     bool // ClientAuth usage instead of ServerAuth
+	Validity   time.Duration
+}
+
+// NewCA creates a self-signed CA and writes ca.crt/ca.key into dir.
+func NewCA(dir, commonName string, validity time.Duration) (*Authority, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generate CA key: %w", err)
+	}
+
+	tmpl, err := baseTemplate(commonName, validity)
+	if err != nil {
+		return nil, err
+	}
+	tmpl.IsCA = true
+	tmpl.BasicConstraintsValid = true
+	tmpl.MaxPathLenZero = true
+	tmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+	tmpl.SubjectKeyId = keyID(&key.PublicKey)
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("create CA certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writePair(dir, CACert, CAKey, der, key); err != nil {
+		return nil, err
+	}
+	return &Authority{Cert: cert, Key: key}, nil
+}
+
+// LoadCA reads ca.crt/ca.key from dir.
+func LoadCA(dir string) (*Authority, error) {
+	certPEM, err := os.ReadFile(filepath.Join(dir, CACert))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoCA
+	}
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := os.ReadFile(filepath.Join(dir, CAKey))
+	if err != nil {
+		return nil, fmt.Errorf("read CA key: %w", err)
+	}
+
+	cert, err := parseCert(certPEM)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", CACert, err)
+	}
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("parse %s: no PEM data", CAKey)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", CAKey, err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("parse %s: unsupported key type", CAKey)
+	}
+	return &Authority{Cert: cert, Key: signer}, nil
+}
+
+// Issue signs a leaf certificate and writes it as certFile/keyFile into dir.
+func (a *Authority) Issue(dir, certFile, keyFile string, req Request) (*x509.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generate key: %w", err)
+	}
+
+	tmpl, err := baseTemplate(req.CommonName, req.Validity)
+	if err != nil {
+		return nil, err
+	}
+	tmpl.KeyUsage = x509.KeyUsageDigitalSignature
+	tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	if req.Client {
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	}
+	tmpl.DNSNames = req.DNSNames
+	tmpl.IPAddresses = req.IPs
+	tmpl.AuthorityKeyId = a.Cert.SubjectKeyId
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, a.Cert, &key.PublicKey, a.Key)
+	if err != nil {
+		return nil, fmt.Errorf("sign certificate: %w", err)
+	}
+	if err := writePair(dir, certFile, keyFile, der, key); err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}
+
+// ServerHosts returns the SANs a service needs: loopback plus its docker-compose names.
+func ServerHosts(service string, extra []string) ([]string, []net.IP) {
+	names := []string{"localhost"}
+	if service != "" {
+		// Service key and container_name from docker-compose.yml
+		names = append(names, service, service+"-app")
+	}
+	ips := []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1")}
+
+	for _, h := range extra {
+		if ip := net.ParseIP(h); ip != nil {
+			ips = append(ips, ip)
+		} else {
+			names = append(names, h)
+		}
+	}
+	return dedupe(names), ips
+}
+
+// Info summarizes a certificate file.
+type Info struct {
+	File      string    `json:"file"`
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	IsCA      bool      `json:"is_ca"`
+	Usage     string    `json:"usage"` // ca | server | client
+	DNSNames  []string  `json:"dns_names,omitempty"`
+	IPs       []string  `json:"ips,omitempty"`
+	NotBefore time.Time `json:"not_before"`
+	NotAfter  time.Time `json:"not_after"`
+	DaysLeft  int       `json:"days_left"`
+	Verified  bool      `json:"verified"` // Chains to the directory's CA
+	Error     string    `json:"error,omitempty"`
+}
+
+// Inspect describes every *.crt in dir, checking leaves against the local CA.
+func Inspect(dir string, now time.Time) ([]Info, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.crt"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+
+	var roots *x509.CertPool
+	if ca, err := LoadCA(dir); err == nil {
+		roots = x509.NewCertPool()
+		roots.AddCert(ca.Cert)
+	}
+
+	out := make([]Info, 0, len(matches))
+	for _, path := range matches {
+		info := Info{File: filepath.Base(path)}
+		data, err := os.ReadFile(path)
+		if err == nil {
+			var cert *x509.Certificate
+			if cert, err = parseCert(data); err == nil {
+				describe(&info, cert, roots, now)
+			}
+		}
+		if err != nil {
+			info.Error = err.Error()
+		}
+		out = append(out, info)
+	}
+	return out, nil
+}
+
+func describe(info *Info, cert *x509.Certificate, roots *x509.CertPool, now time.Time) {
+	info.Subject = cert.Subject.CommonName
+	info.Issuer = cert.Issuer.CommonName
+	info.IsCA = cert.IsCA
+	info.DNSNames = cert.DNSNames
+	for _, ip := range cert.IPAddresses {
+		info.IPs = append(info.IPs, ip.String())
+	}
+	info.NotBefore, info.NotAfter = cert.NotBefore, cert.NotAfter
+	// Rounded down, so an expired certificate is negative rather than 0.
+	info.DaysLeft = int(math.Floor(cert.NotAfter.Sub(now).Hours() / 24))
+
+	info.Usage = "server"
+	switch {
+	case cert.IsCA:
+		info.Usage = "ca"
+	case len(cert.ExtKeyUsage) > 0 && cert.ExtKeyUsage[0] == x509.ExtKeyUsageClientAuth:
+		info.Usage = "client"
+	}
+
+	if roots == nil {
+		return
+	}
+	usage := x509.ExtKeyUsageServerAuth
+	if info.Usage == "client" {
+		usage = x509.ExtKeyUsageClientAuth
+	}
+	_, err := cert.Verify(x509.VerifyOptions{Roots: roots, CurrentTime: now, KeyUsages: []x509.ExtKeyUsage{usage}})
+	info.Verified = err == nil
+	if err != nil {
+		info.Error = err.Error()
+	}
+}
+
+func baseTemplate(commonName string, validity time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("generate serial: %w", err)
+	}
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName, Organization: []string{organization}},
+		NotBefore:    now.Add(-5 * time.Minute), // Tolerate container clock skew
+		NotAfter:     now.Add(validity),
+	}, nil
+}
+
+// writePair writes the certificate (0644) and its PKCS#8 key (0600).
+func writePair(dir, certFile, keyFile string, der []byte, key *ecdsa.PrivateKey) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("marshal key: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, keyFile), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, certFile), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644)
+}
+
+func parseCert(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("no PEM certificate")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func keyID(pub *ecdsa.PublicKey) []byte {
+	der, _ := x509.MarshalPKIXPublicKey(pub)
+	sum := sha256.Sum256(der)
+	return sum[:20]
+}
+
+func dedupe(list []string) []string {
+	seen := map[string]bool{}
+	out := list[:0]
+	for _, s := range list {
+		s = strings.TrimSpace(s)
+		if s != "" && !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,156 @@
+package certs
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestServerHosts(t *testing.T) {
+	names, ips := ServerHosts("svc-order", []string{"api.local", "10.0.0.5", "localhost", " ", "svc-order", "::1"})
+	if want := []string{"localhost", "svc-order", "svc-order-app", "api.local"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("names = %v, want %v", names, want)
+	}
+	var got []string
+	for _, ip := range ips {
+		got = append(got, ip.String())
+	}
+	if want := []string{"127.0.0.1", "::1", "10.0.0.5", "::1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ips = %v, want %v", got, want)
+	}
+
+	names, _ = ServerHosts("", nil)
+	if want := []string{"localhost"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("names without a service = %v, want %v", names, want)
+	}
+}
+
+func TestIssueAndInspect(t *testing.T) {
+	dir := t.TempDir()
+	ca, err := NewCA(dir, "Test CA", 365*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names, ips := ServerHosts("svc-order", nil)
+	server, err := ca.Issue(dir, ServerCert, ServerKey, Request{CommonName: "svc-order", DNSNames: names, IPs: ips, Validity: 30 * 24 * time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ca.Issue(dir, ClientCert, ClientKey, Request{CommonName: "client", Client: true, Validity: 24 * time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+	for _, host := range []string{"localhost", "svc-order", "svc-order-app", "127.0.0.1", "::1"} {
+		if err := server.VerifyHostname(host); err != nil {
+			t.Errorf("server certificate: %v", err)
+		}
+	}
+	if fi, err := os.Stat(filepath.Join(dir, ServerKey)); err != nil || fi.Mode().Perm() != 0600 {
+		t.Errorf("%s mode = %v (%v), want 0600", ServerKey, fi.Mode().Perm(), err)
+	}
+
+	now := time.Now()
+	infos, err := Inspect(dir, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byFile := map[string]Info{}
+	for _, info := range infos {
+		byFile[info.File] = info
+	}
+	tests := []struct {
+		file     string
+		usage    string
+		daysLeft int
+	}{
+		{CACert, "ca", 364},
+		{ClientCert, "client", 0},
+		{ServerCert, "server", 29},
+	}
+	for _, tt := range tests {
+		info, ok := byFile[tt.file]
+		if !ok {
+			t.Errorf("%s not inspected", tt.file)
+			continue
+		}
+		if info.Usage != tt.usage || info.DaysLeft != tt.daysLeft || !info.Verified || info.Error != "" {
+			t.Errorf("%s = usage %s, %d days left, verified %t, error %q; want %s, %d days, verified",
+				tt.file, info.Usage, info.DaysLeft, info.Verified, info.Error, tt.usage, tt.daysLeft)
+		}
+	}
+
+	// A day later the client certificate has expired.
+	infos, err = Inspect(dir, now.Add(25*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, info := range infos {
+		if info.File == ClientCert && (info.Verified || info.DaysLeft >= 0 || info.Error == "") {
+			t.Errorf("expired %s = verified %t, %d days left, error %q", ClientCert, info.Verified, info.DaysLeft, info.Error)
+		}
+	}
+}
+
+func TestInspectAgainstAnotherCA(t *testing.T) {
+	dir, other := t.TempDir(), t.TempDir()
+	ca, err := NewCA(other, "Other CA", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ca.Issue(dir, ServerCert, ServerKey, Request{CommonName: "svc", Validity: time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewCA(dir, "Local CA", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := LoadCA(dir)
+	if err != nil || reloaded.Cert.Subject.CommonName != "Local CA" {
+		t.Fatalf("LoadCA = %v, %v", reloaded, err)
+	}
+
+	infos, err := Inspect(dir, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, info := range infos {
+		if info.File == ServerCert && (info.Verified || info.Error == "") {
+			t.Errorf("%s signed by another CA = verified %t, error %q", ServerCert, info.Verified, info.Error)
+		}
+	}
+}
+
+func TestLoadCAMissing(t *testing.T) {
+	if _, err := LoadCA(t.TempDir()); err != ErrNoCA {
+		t.Fatalf("LoadCA = %v, want ErrNoCA", err)
+	}
+}
+
+func TestUpdateEnvFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	initial := "# mTLS\nHTTP_MTLS_ENABLED=false\n# TLS_CA_FILE=old\nAPP_PORT=8080\n"
+	if err := os.WriteFile(path, []byte(initial), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err := UpdateEnvFile(path, map[string]string{
+		"HTTP_MTLS_ENABLED": "true",
+		"TLS_CA_FILE":       "certs/ca.crt",
+		"TLS_CERT_FILE":     "certs/server.crt",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(path)
+	want := "# mTLS\nHTTP_MTLS_ENABLED=true\n# TLS_CA_FILE=old\nAPP_PORT=8080\nTLS_CA_FILE=certs/ca.crt\nTLS_CERT_FILE=certs/server.crt\n"
+	if string(got) != want {
+		t.Errorf(".env =\n%s\nwant\n%s", got, want)
+	}
+
+	missing := filepath.Join(t.TempDir(), ".env")
+	if err := UpdateEnvFile(missing, map[string]string{"A": "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(missing); string(got) != "A=1\n" {
+		t.Errorf("new .env = %q, want %q", got, "A=1\n")
+	}
+}
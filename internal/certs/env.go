@@ -0,0 +1,46 @@
+package certs
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+// UpdateEnvFile sets KEY=value pairs in a dotenv file, keeping comments and order.
+// Keys that are missing (or only present commented out) are appended.
+func UpdateEnvFile(path string, values map[string]string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(data) == 0 {
+		lines = nil
+	}
+
+	done := map[string]bool{}
+	for i, line := range lines {
+		key, _, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok || strings.HasPrefix(key, "#") {
+			continue
+		}
+		if v, want := values[key]; want {
+			lines[i] = key + "=" + v
+			done[key] = true
+		}
+	}
+
+	var missing []string
+	for key := range values {
+		if !done[key] {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	for _, key := range missing {
+		lines = append(lines, key+"="+values[key])
+	}
+
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
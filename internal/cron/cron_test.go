@@ -0,0 +1,139 @@
+package cron
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestParseNext(t *testing.T) {
+	from := time.Date(2024, 3, 9, 10, 7, 30, 0, time.UTC) // A Saturday
+	tests := []struct {
+		name string
+		expr string
+		want time.Time
+	}{
+		{"every minute", "* * * * *", time.Date(2024, 3, 9, 10, 8, 0, 0, time.UTC)},
+		{"step", "*/15 * * * *", time.Date(2024, 3, 9, 10, 15, 0, 0, time.UTC)},
+		{"step over a range", "10-40/20 * * * *", time.Date(2024, 3, 9, 10, 10, 0, 0, time.UTC)},
+		{"step over a range, past its end", "0-5/5 * * * *", time.Date(2024, 3, 9, 11, 0, 0, 0, time.UTC)},
+		{"step from a value", "50/5 * * * *", time.Date(2024, 3, 9, 10, 50, 0, 0, time.UTC)},
+		{"weekday range", "0 9 * * mon-fri", time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC)},
+		{"list", "30 2 1,15 * *", time.Date(2024, 3, 15, 2, 30, 0, 0, time.UTC)},
+		{"day of month or week, month day first", "0 0 13 * fri", time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC)},
+		{"day of month or week, weekday first", "0 0 20 * mon", time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)},
+		{"day of month with any weekday", "0 0 13 * *", time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC)},
+		{"weekday with any day of month", "0 0 * * fri", time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"sunday as 7", "0 0 * * 7", time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{"leap day", "0 0 29 feb *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"@daily", "@daily", time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", "@hourly", time.Date(2024, 3, 9, 11, 0, 0, 0, time.UTC)},
+		{"@weekly", "@weekly", time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", "@monthly", time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", "@yearly", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.expr, err)
+			}
+			if got := s.Next(from); !got.Equal(tt.want) {
+				t.Errorf("Parse(%q).Next = %s, want %s", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextIsStrictlyAfter(t *testing.T) {
+	s, err := Parse("@daily")
+	if err != nil {
+		t.Fatal(err)
+	}
+	midnight := time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC)
+	if got, want := s.Next(midnight), midnight.AddDate(0, 0, 1); !got.Equal(want) {
+		t.Errorf("Next(%s) = %s, want %s", midnight, got, want)
+	}
+}
+
+func TestParseRejectsInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"* * * foo *",
+		"@every",
+		"0 0 30 feb *", // Never fires
+	} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q) accepted it", expr)
+		}
+	}
+}
+
+// templateNames maps the identifiers of templates/job/cron.go.tmpl, prefixed
+// so they don't clash in the worker package, to the ones here.
+var templateNames = map[string]string{
+	"ParseSchedule":  "Parse",
+	"parseCronField": "parseField",
+	"cronBounds":     "bounds",
+	"cronMinutes":    "minutes",
+	"cronHours":      "hours",
+	"cronDays":       "days",
+	"cronMonths":     "months",
+	"cronWeekdays":   "weekdays",
+	"cronMacros":     "macros",
+	"cronValue":      "value",
+}
+
+// The generated services parse schedules with their own copy of this file, and
+// 'new job' must accept exactly the schedules they do.
+func TestTemplateMatchesParser(t *testing.T) {
+	want := normalize(t, "cron.go", nil)
+	got := normalize(t, "../../templates/job/cron.go.tmpl", templateNames)
+	if got != want {
+		t.Fatalf("templates/job/cron.go.tmpl differs from internal/cron/cron.go; keep the code the same.\n--- template (renamed) ---\n%s\n--- cron.go ---\n%s", got, want)
+	}
+}
+
+// normalize prints the code of file without comments, in package cron and with
+// its identifiers renamed.
+func normalize(t *testing.T, file string, rename map[string]string) string {
+	t.Helper()
+	src, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Name.Name = "cron"
+	ast.Inspect(f, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok {
+			if to, ok := rename[id.Name]; ok {
+				id.Name = to
+			}
+		}
+		return true
+	})
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, token.NewFileSet(), f); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
@@ -0,0 +1,143 @@
+package dlq
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIClient calls the operator API of a running service.
+type APIClient struct {
+	BaseURL string // e.g. http://localhost:8080
+	Token   string // X-Admin-Token
+	Bearer  string // Optional JWT, when the service runs with AUTH_ENABLED
+	HTTP    *http.Client
+}
+
+// NewAPIClient returns a client for the service at baseURL.
+func NewAPIClient(baseURL, token, bearer string) *APIClient {
+	return &APIClient{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		Token:   token,
+		Bearer:  bearer,
+		HTTP:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (c *APIClient) List(ctx context.Context, f Filter) ([]Message, error) {
+	q := url.Values{}
+	for k, v := range map[string]string{"consumer": f.Consumer, "topic": f.Topic, "status": f.Status, "error": f.Error} {
+		if v != "" {
+			q.Set(k, v)
+		}
+	}
+	if f.From != nil {
+		q.Set("from", f.From.Format(time.RFC3339))
+	}
+	if f.To != nil {
+		q.Set("to", f.To.Format(time.RFC3339))
+	}
+	if f.Limit > 0 {
+		q.Set("limit", strconv.Itoa(f.Limit))
+	}
+	var res struct {
+		Data []Message `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/admin/dlq?"+q.Encode(), nil, &res); err != nil {
+		return nil, err
+	}
+	if len(f.IDs) == 0 {
+		return res.Data, nil
+	}
+	// The list endpoint has no ID filter; fetch each one instead.
+	msgs := []Message{}
+	for _, id := range f.IDs {
+		m, err := c.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, *m)
+	}
+	return msgs, nil
+}
+
+func (c *APIClient) Get(ctx context.Context, id string) (*Message, error) {
+	var m Message
+	if err := c.do(ctx, http.MethodGet, "/admin/dlq/"+url.PathEscape(id), nil, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (c *APIClient) Redrive(ctx context.Context, f Filter) (int64, error) {
+	return c.action(ctx, "/admin/dlq/redrive", f)
+}
+
+func (c *APIClient) Discard(ctx context.Context, f Filter) (int64, error) {
+	return c.action(ctx, "/admin/dlq/discard", f)
+}
+
+func (c *APIClient) Close() error { return nil }
+
+func (c *APIClient) action(ctx context.Context, path string, f Filter) (int64, error) {
+	var res struct {
+		Affected int64 `json:"affected"`
+	}
+	if err := c.do(ctx, http.MethodPost, path, f, &res); err != nil {
+		return 0, err
+	}
+	return res.Affected, nil
+}
+
+func (c *APIClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Admin-Token", c.Token)
+	if c.Bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Bearer)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("%s %s: %s (%s)", method, path, apiErr.Error.Message, apiErr.Error.Code)
+		}
+		return fmt.Errorf("%s %s: %s", method, path, resp.Status)
+	}
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,158 @@
+package dlq
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	_ "github.com/jackc/pgx/v5/stdlib" // database/sql driver "pgx"
+)
+
+// The queries mirror templates/app/internal/adapter/repository/dead_letter_repository.go.tmpl;
+// keep the two in sync.
+
+const messageColumns = `id::text, consumer, topic, COALESCE(dlq_topic, ''), message_id, COALESCE(key, ''), convert_from(payload, 'UTF8'), status, attempts, COALESCE(last_error, ''), redrive_count, first_failed_at, dead_at, redriven_at, updated_at`
+
+var (
+	redriveFrom = []string{StatusDead, StatusDiscarded}
+	discardFrom = []string{StatusDead, StatusQueued}
+)
+
+// DBClient works on the dead_letters table directly, for when the service's
+// API is down. The service's dlq worker still has to run to publish redrives.
+type DBClient struct {
+	db *sql.DB
+}
+
+// OpenDB connects to the service database at dsn.
+func OpenDB(ctx context.Context, dsn string) (*DBClient, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &DBClient{db: db}, nil
+}
+
+func (c *DBClient) List(ctx context.Context, f Filter) ([]Message, error) {
+	where, args := whereClause(f)
+	limit := f.Limit
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	args = append(args, limit)
+	query := fmt.Sprintf(`SELECT %s FROM dead_letters %s ORDER BY first_failed_at DESC LIMIT $%d`, messageColumns, where, len(args))
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	msgs := []Message{}
+	for rows.Next() {
+		m, err := scanMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, *m)
+	}
+	return msgs, rows.Err()
+}
+
+func (c *DBClient) Get(ctx context.Context, id string) (*Message, error) {
+	m, err := scanMessage(c.db.QueryRowContext(ctx, `SELECT `+messageColumns+` FROM dead_letters WHERE id::text = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("dead letter %s not found", id)
+	}
+	return m, err
+}
+
+func (c *DBClient) Redrive(ctx context.Context, f Filter) (int64, error) {
+	return c.update(ctx, f, redriveFrom, `status = 'QUEUED', updated_at = NOW()`)
+}
+
+func (c *DBClient) Discard(ctx context.Context, f Filter) (int64, error) {
+	return c.update(ctx, f, discardFrom, `status = 'DISCARDED', updated_at = NOW()`)
+}
+
+func (c *DBClient) Close() error { return c.db.Close() }
+
+func (c *DBClient) update(ctx context.Context, f Filter, from []string, set string) (int64, error) {
+	if f.Empty() {
+		return 0, errors.New("a filter is required")
+	}
+	if err := checkStatus(f.Status, from); err != nil {
+		return 0, err
+	}
+	where, args := whereClause(f)
+	args = append(args, from)
+	res, err := c.db.ExecContext(ctx, fmt.Sprintf(`UPDATE dead_letters SET %s %s AND status = ANY($%d)`, set, where, len(args)), args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// checkStatus rejects a status filter the action can never match.
+func checkStatus(status string, allowed []string) error {
+	if status == "" {
+		return nil
+	}
+	for _, s := range allowed {
+		if s == status {
+			return nil
+		}
+	}
+	return fmt.Errorf("status must be one of %s", strings.Join(allowed, ", "))
+}
+
+func whereClause(f Filter) (string, []interface{}) {
+	conds := []string{"TRUE"}
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if len(f.IDs) > 0 {
+		add("id::text = ANY($%d)", f.IDs)
+	}
+	if f.Consumer != "" {
+		add("consumer = $%d", f.Consumer)
+	}
+	if f.Topic != "" {
+		add("topic = $%d", f.Topic)
+	}
+	if f.Status != "" {
+		add("status = $%d", f.Status)
+	}
+	if f.Error != "" {
+		add("last_error ILIKE '%%' || $%d || '%%'", f.Error)
+	}
+	if f.From != nil {
+		add("first_failed_at >= $%d", *f.From)
+	}
+	if f.To != nil {
+		add("first_failed_at < $%d", *f.To)
+	}
+	return "WHERE " + strings.Join(conds, " AND "), args
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanMessage(row scanner) (*Message, error) {
+	var m Message
+	err := row.Scan(&m.ID, &m.Consumer, &m.Topic, &m.DLQTopic, &m.MessageID, &m.Key, &m.Payload, &m.Status,
+		&m.Attempts, &m.LastError, &m.RedriveCount, &m.FirstFailedAt, &m.DeadAt, &m.RedrivenAt, &m.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
@@ -0,0 +1,70 @@
+// Package dlq browses and redrives the dead letters of a service's Kafka
+// consumers, either through the service's operator API (/admin/dlq) or
+// directly in Postgres. The service's dlq worker mode does the publishing.
+package dlq
+
+import (
+	"context"
+	"time"
+)
+
+// Statuses of a dead_letters row.
+const (
+	StatusFailing   = "FAILING"   // Failed at least once, still being retried
+	StatusDead      = "DEAD"      // On the DLQ topic
+	StatusQueued    = "QUEUED"    // Waiting for the redriver
+	StatusRedriving = "REDRIVING" // Claimed by a redriver
+	StatusRedriven  = "REDRIVEN"  // Published back to its source topic
+	StatusDiscarded = "DISCARDED"
+)
+
+// Statuses lists every status, for flag validation.
+var Statuses = []string{StatusFailing, StatusDead, StatusQueued, StatusRedriving, StatusRedriven, StatusDiscarded}
+
+// Message mirrors dto.DeadLetterResponse of the generated service.
+type Message struct {
+	ID            string     `json:"id"`
+	Consumer      string     `json:"consumer"`
+	Topic         string     `json:"topic"`
+	DLQTopic      string     `json:"dlq_topic,omitempty"`
+	MessageID     string     `json:"message_id"`
+	Key           string     `json:"key,omitempty"`
+	Payload       string     `json:"payload"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	RedriveCount  int        `json:"redrive_count"`
+	FirstFailedAt time.Time  `json:"first_failed_at"`
+	DeadAt        *time.Time `json:"dead_at,omitempty"`
+	RedrivenAt    *time.Time `json:"redriven_at,omitempty"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// Filter mirrors dto.DeadLetterFilterRequest. Zero fields match everything.
+type Filter struct {
+	IDs      []string   `json:"ids,omitempty"`
+	Consumer string     `json:"consumer,omitempty"`
+	Topic    string     `json:"topic,omitempty"`
+	Status   string     `json:"status,omitempty"`
+	Error    string     `json:"error,omitempty"` // Substring of the last error, case-insensitive
+	From     *time.Time `json:"from,omitempty"`  // First failure
+	To       *time.Time `json:"to,omitempty"`
+	Limit    int        `json:"-"` // List only
+}
+
+// Empty reports whether f selects every row. Bulk actions refuse it.
+func (f Filter) Empty() bool {
+	return len(f.IDs) == 0 && f.Consumer == "" && f.Topic == "" && f.Status == "" && f.Error == "" && f.From == nil && f.To == nil
+}
+
+// Client is implemented by the API and the direct-database backends.
+type Client interface {
+	List(ctx context.Context, f Filter) ([]Message, error)
+	Get(ctx context.Context, id string) (*Message, error)
+	// Redrive queues DEAD or DISCARDED rows for the service's redriver, which
+	// publishes them back to their source topic.
+	Redrive(ctx context.Context, f Filter) (int64, error)
+	// Discard marks DEAD or QUEUED rows DISCARDED.
+	Discard(ctx context.Context, f Filter) (int64, error)
+	Close() error
+}
@@ -0,0 +1,96 @@
+package events
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Change is a backward-incompatible difference between two schemas.
+type Change struct {
+	Path   string `json:"path"` // JSON pointer-like location, "" for the root
+	Reason string `json:"reason"`
+}
+
+func (c Change) String() string {
+	if c.Path == "" {
+		return c.Reason
+	}
+	return c.Path + ": " + c.Reason
+}
+
+// Compare lists the changes from old to new that break consumers built
+// against old. A change is compatible when every payload valid under new is
+// still valid under old, which allows adding properties and narrowing types
+// but not removing or loosening anything a consumer relies on.
+func Compare(old, new *Schema) []Change {
+	var out []Change
+	compare(old, new, "", &out)
+	return out
+}
+
+func compare(old, new *Schema, at string, out *[]Change) {
+	add := func(format string, args ...any) {
+		*out = append(*out, Change{Path: at, Reason: fmt.Sprintf(format, args...)})
+	}
+
+	// An open schema ({}) accepts anything, so nothing can break it.
+	if len(old.Type) > 0 {
+		if len(new.Type) == 0 {
+			add("type %s became unconstrained", typesString(old.Type))
+			return
+		}
+		for _, t := range new.Type {
+			if !old.Type.Has(t) && !(t == "integer" && old.Type.Has("number")) {
+				add("type changed from %s to %s", typesString(old.Type), typesString(new.Type))
+				return
+			}
+		}
+	}
+	if old.Format != "" && new.Format != old.Format {
+		add("format changed from %q to %q", old.Format, new.Format)
+	}
+	if old.ContentEncoding != new.ContentEncoding && old.ContentEncoding != "" {
+		add("contentEncoding changed from %q to %q", old.ContentEncoding, new.ContentEncoding)
+	}
+
+	required := map[string]bool{}
+	for _, name := range new.Required {
+		required[name] = true
+	}
+	for _, name := range old.Required {
+		if required[name] {
+			continue
+		}
+		if _, still := new.Properties[name]; still {
+			*out = append(*out, Change{Path: at + "/" + name, Reason: "became optional"})
+		} else {
+			*out = append(*out, Change{Path: at + "/" + name, Reason: "required property removed"})
+		}
+	}
+
+	for _, name := range sortedKeys(old.Properties) {
+		if n, ok := new.Properties[name]; ok {
+			compare(old.Properties[name], n, at+"/"+name, out)
+		}
+	}
+	if old.Items != nil && new.Items != nil {
+		compare(old.Items, new.Items, at+"/items", out)
+	}
+	if old.AdditionalProperties != nil && new.AdditionalProperties != nil {
+		compare(old.AdditionalProperties, new.AdditionalProperties, at+"/additionalProperties", out)
+	}
+}
+
+func typesString(t Types) string {
+	return strings.Join(t, "|")
+}
+
+func sortedKeys(m map[string]*Schema) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
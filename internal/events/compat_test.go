@@ -0,0 +1,147 @@
+package events
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	const base = `{"type": "object", "properties": {
+		"id": {"type": "string", "format": "uuid"},
+		"amount": {"type": "number"},
+		"note": {"type": ["string", "null"]},
+		"tags": {"type": ["array", "null"], "items": {"type": "string"}}
+	}, "required": ["amount", "id", "tags"]}`
+
+	tests := []struct {
+		name     string
+		old, new string
+		want     []Change
+	}{
+		{"unchanged", base, base, nil},
+		{
+			"property added",
+			base,
+			`{"type": "object", "properties": {
+				"id": {"type": "string", "format": "uuid"},
+				"amount": {"type": "number"},
+				"note": {"type": ["string", "null"]},
+				"tags": {"type": ["array", "null"], "items": {"type": "string"}},
+				"currency": {"type": "string"}
+			}, "required": ["amount", "currency", "id", "tags"]}`,
+			nil,
+		},
+		{
+			"optional property became required",
+			`{"type": "object", "properties": {"note": {"type": "string"}}}`,
+			`{"type": "object", "properties": {"note": {"type": "string"}}, "required": ["note"]}`,
+			nil,
+		},
+		{
+			"optional property removed",
+			`{"type": "object", "properties": {"id": {"type": "string"}, "note": {"type": "string"}}, "required": ["id"]}`,
+			`{"type": "object", "properties": {"id": {"type": "string"}}, "required": ["id"]}`,
+			nil,
+		},
+		{"number narrowed to integer", `{"type": "number"}`, `{"type": "integer"}`, nil},
+		{"null dropped", `{"type": ["string", "null"]}`, `{"type": "string"}`, nil},
+		{"open schema constrained", `{}`, `{"type": "string"}`, nil},
+		{
+			"required property became optional",
+			`{"type": "object", "properties": {"id": {"type": "string"}}, "required": ["id"]}`,
+			`{"type": "object", "properties": {"id": {"type": "string"}}}`,
+			[]Change{{Path: "/id", Reason: "became optional"}},
+		},
+		{
+			"required property removed",
+			`{"type": "object", "properties": {"id": {"type": "string"}}, "required": ["id"]}`,
+			`{"type": "object", "properties": {}}`,
+			[]Change{{Path: "/id", Reason: "required property removed"}},
+		},
+		{
+			"integer widened to number",
+			`{"type": "integer"}`, `{"type": "number"}`,
+			[]Change{{Reason: "type changed from integer to number"}},
+		},
+		{
+			"type changed",
+			`{"type": "object", "properties": {"amount": {"type": "number"}}}`,
+			`{"type": "object", "properties": {"amount": {"type": "string"}}}`,
+			[]Change{{Path: "/amount", Reason: "type changed from number to string"}},
+		},
+		{
+			"became nullable",
+			`{"type": "string"}`, `{"type": ["string", "null"]}`,
+			[]Change{{Reason: "type changed from string to string|null"}},
+		},
+		{
+			"type dropped",
+			`{"type": "string"}`, `{}`,
+			[]Change{{Reason: "type string became unconstrained"}},
+		},
+		{
+			"format changed",
+			`{"type": "string", "format": "uuid"}`, `{"type": "string", "format": "date-time"}`,
+			[]Change{{Reason: `format changed from "uuid" to "date-time"`}},
+		},
+		{
+			"format dropped",
+			`{"type": "string", "format": "uuid"}`, `{"type": "string"}`,
+			[]Change{{Reason: `format changed from "uuid" to ""`}},
+		},
+		{
+			"contentEncoding dropped",
+			`{"type": "string", "contentEncoding": "base64"}`, `{"type": "string"}`,
+			[]Change{{Reason: `contentEncoding changed from "base64" to ""`}},
+		},
+		{
+			"array items changed",
+			`{"type": "array", "items": {"type": "string"}}`,
+			`{"type": "array", "items": {"type": "integer"}}`,
+			[]Change{{Path: "/items", Reason: "type changed from string to integer"}},
+		},
+		{
+			"map values changed",
+			`{"type": "object", "additionalProperties": {"type": "integer"}}`,
+			`{"type": "object", "additionalProperties": {"type": "boolean"}}`,
+			[]Change{{Path: "/additionalProperties", Reason: "type changed from integer to boolean"}},
+		},
+		{
+			"nested required property became optional",
+			`{"type": "object", "properties": {"address": {"type": "object", "properties": {"city": {"type": "string"}}, "required": ["city"]}}}`,
+			`{"type": "object", "properties": {"address": {"type": "object", "properties": {"city": {"type": "string"}}}}}`,
+			[]Change{{Path: "/address/city", Reason: "became optional"}},
+		},
+		{
+			"several changes",
+			base,
+			`{"type": "object", "properties": {
+				"id": {"type": "string"},
+				"amount": {"type": "number"},
+				"note": {"type": "integer"}
+			}, "required": ["amount"]}`,
+			[]Change{
+				{Path: "/id", Reason: "became optional"},
+				{Path: "/tags", Reason: "required property removed"},
+				{Path: "/id", Reason: `format changed from "uuid" to ""`},
+				{Path: "/note", Reason: "type changed from string|null to integer"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Compare(mustParse(t, tt.old), mustParse(t, tt.new)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Compare = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func mustParse(t *testing.T, schema string) *Schema {
+	t.Helper()
+	s, err := Parse([]byte(schema))
+	if err != nil {
+		t.Fatalf("Parse(%s): %v", schema, err)
+	}
+	return s
+}
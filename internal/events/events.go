@@ -0,0 +1,234 @@
+// Package events reads the event contracts of a generated service
+// (internal/core/event), renders their JSON Schemas and checks schema changes
+// for backward compatibility.
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	goast "go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Dir holds the contracts, relative to the project root.
+	Dir = "internal/core/event"
+	// SchemaDir holds the generated schemas, relative to the project root.
+	SchemaDir = "api/events"
+)
+
+// Contract is an event struct with a fixed topic and schema version.
+type Contract struct {
+	Name    string // Go type name
+	Topic   string
+	Version int
+	Schema  *Schema
+}
+
+// Path is the schema file of c relative to the project root.
+func (c Contract) Path() string {
+	return path.Join(SchemaDir, c.Topic, fmt.Sprintf("v%d.json", c.Version))
+}
+
+// Render returns the schema file content of c.
+func (c Contract) Render() ([]byte, error) {
+	b, err := json.MarshalIndent(c.Schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// Load parses the contracts of the service rooted at root, sorted by topic and
+// version. A service without internal/core/event has none.
+func Load(root string) ([]Contract, error) {
+	dir := filepath.Join(root, filepath.FromSlash(Dir))
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	var files []*goast.File
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", name, err)
+		}
+		files = append(files, f)
+	}
+
+	p := newPkg(files)
+	var out []Contract
+	seen := map[string]string{}
+	for name, m := range p.methods {
+		if m.topic == "" || m.version == 0 {
+			continue
+		}
+		st, ok := p.structs[name]
+		if !ok {
+			continue
+		}
+		c := Contract{Name: name, Topic: m.topic, Version: m.version}
+		if other, dup := seen[c.Path()]; dup {
+			return nil, fmt.Errorf("%s and %s both declare %s v%d", other, name, c.Topic, c.Version)
+		}
+		seen[c.Path()] = name
+
+		g := &generator{pkg: p}
+		g.enter(name)
+		c.Schema = g.object(st, nil)
+		c.Schema.Draft = Draft
+		c.Schema.ID = path.Join(c.Topic, fmt.Sprintf("v%d.json", c.Version))
+		c.Schema.Title = name
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Topic != out[j].Topic {
+			return out[i].Topic < out[j].Topic
+		}
+		return out[i].Version < out[j].Version
+	})
+	return out, nil
+}
+
+// Topics maps the event types declared in files to their topics. Types whose
+// Topic() is not a literal or a package constant are left out.
+func Topics(files []*goast.File) map[string]string {
+	p := newPkg(files)
+	out := map[string]string{}
+	for name, m := range p.methods {
+		if m.topic != "" {
+			out[name] = m.topic
+		}
+	}
+	return out
+}
+
+// pkg is the syntax of the event package.
+type pkg struct {
+	structs map[string]*goast.StructType
+	consts  map[string]string
+	methods map[string]*contractMethods
+}
+
+type contractMethods struct {
+	topic   string
+	version int
+}
+
+func newPkg(files []*goast.File) *pkg {
+	p := &pkg{
+		structs: map[string]*goast.StructType{},
+		consts:  map[string]string{},
+		methods: map[string]*contractMethods{},
+	}
+	var funcs []*goast.FuncDecl
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *goast.FuncDecl:
+				funcs = append(funcs, d)
+			case *goast.GenDecl:
+				for _, spec := range d.Specs {
+					switch s := spec.(type) {
+					case *goast.TypeSpec:
+						if st, ok := s.Type.(*goast.StructType); ok {
+							p.structs[s.Name.Name] = st
+						}
+					case *goast.ValueSpec:
+						if d.Tok != token.CONST {
+							continue
+						}
+						for i, name := range s.Names {
+							if i < len(s.Values) {
+								if v, ok := stringLit(s.Values[i]); ok {
+									p.consts[name.Name] = v
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// Methods last, so constants declared in other files resolve.
+	for _, fn := range funcs {
+		recv := receiverName(fn)
+		if recv == "" {
+			continue
+		}
+		ret := returnExpr(fn)
+		if ret == nil {
+			continue
+		}
+		m := p.methods[recv]
+		if m == nil {
+			m = &contractMethods{}
+			p.methods[recv] = m
+		}
+		switch fn.Name.Name {
+		case "Topic":
+			if v, ok := stringLit(ret); ok {
+				m.topic = v
+			} else if id, ok := ret.(*goast.Ident); ok {
+				m.topic = p.consts[id.Name]
+			}
+		case "SchemaVersion":
+			if lit, ok := ret.(*goast.BasicLit); ok && lit.Kind == token.INT {
+				m.version, _ = strconv.Atoi(lit.Value)
+			}
+		}
+	}
+	return p
+}
+
+// returnExpr is the result of a method whose body is a single return statement.
+func returnExpr(fn *goast.FuncDecl) goast.Expr {
+	if fn.Body == nil || len(fn.Body.List) != 1 {
+		return nil
+	}
+	ret, ok := fn.Body.List[0].(*goast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return nil
+	}
+	return ret.Results[0]
+}
+
+func receiverName(fn *goast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	t := fn.Recv.List[0].Type
+	if star, ok := t.(*goast.StarExpr); ok {
+		t = star.X
+	}
+	if id, ok := t.(*goast.Ident); ok {
+		return id.Name
+	}
+	return ""
+}
+
+func stringLit(e goast.Expr) (string, bool) {
+	lit, ok := e.(*goast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	return s, err == nil
+}
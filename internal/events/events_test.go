@@ -0,0 +1,160 @@
+package events
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const contractsSrc = `package event
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const TopicOrderCreated = "order.created"
+
+type Meta struct {
+	TraceID string ` + "`json:\"trace_id\"`" + `
+}
+
+type Status string
+
+type Line struct {
+	SKU      string ` + "`json:\"sku\"`" + `
+	Quantity int    ` + "`json:\"quantity\"`" + `
+}
+
+type OrderCreated struct {
+	Meta
+	ID        uuid.UUID         ` + "`json:\"id\"`" + `
+	Total     float64           ` + "`json:\"total\"`" + `
+	Status    Status            ` + "`json:\"status\"`" + `
+	Note      *string           ` + "`json:\"note,omitempty\"`" + `
+	Lines     []Line            ` + "`json:\"lines\"`" + `
+	Labels    map[string]string ` + "`json:\"labels,omitempty\"`" + `
+	Raw       json.RawMessage   ` + "`json:\"raw,omitempty\"`" + `
+	Signature []byte            ` + "`json:\"signature\"`" + `
+	CreatedAt time.Time         ` + "`json:\"created_at\"`" + `
+	Internal  string            ` + "`json:\"-\"`" + `
+	Untagged  bool
+	secret    string
+}
+
+func (OrderCreated) Topic() string     { return TopicOrderCreated }
+func (OrderCreated) SchemaVersion() int { return 1 }
+
+type OrderCreatedV2 struct {
+	ID uuid.UUID ` + "`json:\"id\"`" + `
+}
+
+func (OrderCreatedV2) Topic() string     { return "order.created" }
+func (OrderCreatedV2) SchemaVersion() int { return 2 }
+
+// Not a contract: no SchemaVersion.
+type OrderShipped struct{}
+
+func (OrderShipped) Topic() string { return "order.shipped" }
+`
+
+func TestLoad(t *testing.T) {
+	root := writeContracts(t, contractsSrc)
+	contracts, err := Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(contracts) != 2 {
+		t.Fatalf("got %d contracts, want 2", len(contracts))
+	}
+	if c := contracts[1]; c.Name != "OrderCreatedV2" || c.Path() != "api/events/order.created/v2.json" {
+		t.Errorf("second contract = %s at %s, want OrderCreatedV2 at api/events/order.created/v2.json", c.Name, c.Path())
+	}
+
+	c := contracts[0]
+	if c.Name != "OrderCreated" || c.Topic != "order.created" || c.Version != 1 {
+		t.Fatalf("first contract = %s %s v%d, want OrderCreated order.created v1", c.Name, c.Topic, c.Version)
+	}
+	got, err := json.Marshal(c.Schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"$schema":"https://json-schema.org/draft/2020-12/schema","$id":"order.created/v1.json","title":"OrderCreated","type":"object","properties":{` +
+		`"Untagged":{"type":"boolean"},` +
+		`"created_at":{"type":"string","format":"date-time"},` +
+		`"id":{"type":"string","format":"uuid"},` +
+		`"labels":{"type":["object","null"],"additionalProperties":{"type":"string"}},` +
+		`"lines":{"type":["array","null"],"items":{"type":"object","properties":{"quantity":{"type":"integer"},"sku":{"type":"string"}},"required":["quantity","sku"]}},` +
+		`"note":{"type":["string","null"]},` +
+		`"raw":{},` +
+		`"signature":{"type":"string","contentEncoding":"base64"},` +
+		`"status":{},` +
+		`"total":{"type":"number"},` +
+		`"trace_id":{"type":"string"}},` +
+		`"required":["Untagged","created_at","id","lines","signature","status","total","trace_id"]}`
+	if string(got) != want {
+		t.Errorf("schema =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestLoadRejectsDuplicateVersions(t *testing.T) {
+	root := writeContracts(t, `package event
+
+type A struct{}
+
+func (A) Topic() string     { return "t" }
+func (A) SchemaVersion() int { return 1 }
+
+type B struct{}
+
+func (B) Topic() string     { return "t" }
+func (B) SchemaVersion() int { return 1 }
+`)
+	if _, err := Load(root); err == nil || !strings.Contains(err.Error(), "both declare t v1") {
+		t.Fatalf("Load error = %v, want a duplicate version", err)
+	}
+}
+
+func TestLoadWithoutEventPackage(t *testing.T) {
+	contracts, err := Load(t.TempDir())
+	if err != nil || contracts != nil {
+		t.Fatalf("Load = %v, %v; want no contracts", contracts, err)
+	}
+}
+
+func TestRecursiveTypesStayOpen(t *testing.T) {
+	root := writeContracts(t, `package event
+
+type Node struct {
+	Children []Node `+"`json:\"children\"`"+`
+}
+
+func (Node) Topic() string     { return "tree" }
+func (Node) SchemaVersion() int { return 1 }
+`)
+	contracts, err := Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := contracts[0].Schema.Properties["children"].Items
+	if items == nil || len(items.Type) != 0 {
+		t.Fatalf("children items = %+v, want an open schema", items)
+	}
+}
+
+func writeContracts(t *testing.T, src string) string {
+	t.Helper()
+	root := t.TempDir()
+	dir := filepath.Join(root, filepath.FromSlash(Dir))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "order.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
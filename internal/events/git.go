@@ -0,0 +1,59 @@
+package events
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+)
+
+// ErrNoHistory means root is not in a git repository with commits, so
+// nothing has been released yet.
+var ErrNoHistory = errors.New("no git history")
+
+// LatestTag returns the most recent tag reachable from HEAD in the repository
+// containing root, or "" when there is none.
+func LatestTag(root string) (string, error) {
+	out, err := git(root, "describe", "--tags", "--abbrev=0")
+	if err != nil {
+		if _, headErr := git(root, "rev-parse", "--verify", "HEAD"); headErr != nil {
+			return "", fmt.Errorf("%w: %v", ErrNoHistory, headErr)
+		}
+		return "", nil
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Released reads the schema files committed at ref, keyed by their path
+// relative to root. root may be a subdirectory of the repository.
+func Released(root, ref string) (map[string][]byte, error) {
+	list, err := git(root, "ls-tree", "-r", "--name-only", ref, "--", SchemaDir)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string][]byte{}
+	for _, name := range strings.Split(strings.TrimSpace(string(list)), "\n") {
+		if path.Ext(name) != ".json" {
+			continue
+		}
+		b, err := git(root, "show", ref+":./"+name)
+		if err != nil {
+			return nil, err
+		}
+		out[name] = b
+	}
+	return out, nil
+}
+
+func git(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %s", strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
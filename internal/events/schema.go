@@ -0,0 +1,233 @@
+package events
+
+import (
+	"encoding/json"
+	goast "go/ast"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Draft is the JSON Schema dialect of generated schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is the subset of JSON Schema the generator emits.
+type Schema struct {
+	Draft                string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 Types              `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	ContentEncoding      string             `json:"contentEncoding,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// Types is the "type" keyword: a single name or a list when nullable.
+type Types []string
+
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+func (t *Types) UnmarshalJSON(b []byte) error {
+	var one string
+	if json.Unmarshal(b, &one) == nil {
+		*t = Types{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(b, &many); err != nil {
+		return err
+	}
+	*t = many
+	return nil
+}
+
+// Has reports whether name is one of t.
+func (t Types) Has(name string) bool {
+	for _, n := range t {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse decodes a schema file.
+func Parse(b []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Well-known named types outside the event package.
+var namedSchemas = map[string]Schema{
+	"time.Time":       {Type: Types{"string"}, Format: "date-time"},
+	"time.Duration":   {Type: Types{"integer"}},
+	"uuid.UUID":       {Type: Types{"string"}, Format: "uuid"},
+	"json.RawMessage": {},
+}
+
+// generator renders Go types of the event package as schemas, following
+// encoding/json rules.
+type generator struct {
+	pkg      *pkg
+	visiting map[string]bool
+}
+
+// object renders a struct. Embedded structs of the package are flattened like
+// encoding/json does. into is the schema to add properties to, or nil.
+func (g *generator) object(st *goast.StructType, into *Schema) *Schema {
+	if into == nil {
+		into = &Schema{Type: Types{"object"}, Properties: map[string]*Schema{}}
+	}
+	for _, fl := range st.Fields.List {
+		var tag reflect.StructTag
+		if fl.Tag != nil {
+			raw, _ := strconv.Unquote(fl.Tag.Value)
+			tag = reflect.StructTag(raw)
+		}
+		name, opts, _ := strings.Cut(tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+
+		if len(fl.Names) == 0 {
+			if id, ok := deref(fl.Type).(*goast.Ident); ok && name == "" {
+				if embedded, ok := g.pkg.structs[id.Name]; ok && !g.visiting[id.Name] {
+					g.enter(id.Name)
+					g.object(embedded, into)
+					g.leave(id.Name)
+					continue
+				}
+			}
+			// Other embedded types are a field named after the type.
+			embeddedName := typeName(fl.Type)
+			if i := strings.LastIndex(embeddedName, "."); i >= 0 {
+				embeddedName = embeddedName[i+1:]
+			}
+			fl = &goast.Field{Names: []*goast.Ident{goast.NewIdent(embeddedName)}, Type: fl.Type, Tag: fl.Tag}
+		}
+
+		for _, ident := range fl.Names {
+			if !ident.IsExported() {
+				continue
+			}
+			prop := name
+			if prop == "" {
+				prop = ident.Name
+			}
+			into.Properties[prop] = g.typ(fl.Type)
+			if !hasOpt(opts, "omitempty") && !hasOpt(opts, "omitzero") {
+				into.Required = append(into.Required, prop)
+			}
+		}
+	}
+	sort.Strings(into.Required)
+	return into
+}
+
+func (g *generator) typ(e goast.Expr) *Schema {
+	switch t := e.(type) {
+	case *goast.StarExpr:
+		s := g.typ(t.X)
+		if len(s.Type) > 0 && !s.Type.Has("null") {
+			s.Type = append(s.Type, "null")
+		}
+		return s
+
+	case *goast.Ident:
+		switch t.Name {
+		case "string":
+			return &Schema{Type: Types{"string"}}
+		case "bool":
+			return &Schema{Type: Types{"boolean"}}
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "byte", "rune":
+			return &Schema{Type: Types{"integer"}}
+		case "float32", "float64":
+			return &Schema{Type: Types{"number"}}
+		case "any":
+			return &Schema{}
+		}
+		if st, ok := g.pkg.structs[t.Name]; ok && !g.visiting[t.Name] {
+			g.enter(t.Name)
+			defer g.leave(t.Name)
+			return g.object(st, nil)
+		}
+		// Named non-struct types (enums) and recursive references stay open.
+		return &Schema{}
+
+	case *goast.SelectorExpr:
+		if s, ok := namedSchemas[typeName(t)]; ok {
+			return &s
+		}
+		return &Schema{}
+
+	case *goast.ArrayType:
+		if id, ok := t.Elt.(*goast.Ident); ok && id.Name == "byte" {
+			return &Schema{Type: Types{"string"}, ContentEncoding: "base64"}
+		}
+		s := &Schema{Type: Types{"array"}, Items: g.typ(t.Elt)}
+		if t.Len == nil {
+			// A nil slice encodes as null.
+			s.Type = append(s.Type, "null")
+		}
+		return s
+
+	case *goast.MapType:
+		return &Schema{Type: Types{"object", "null"}, AdditionalProperties: g.typ(t.Value)}
+
+	case *goast.StructType:
+		return g.object(t, nil)
+	}
+	// Interfaces and anything unrecognized accept any value.
+	return &Schema{}
+}
+
+func (g *generator) enter(name string) {
+	if g.visiting == nil {
+		g.visiting = map[string]bool{}
+	}
+	g.visiting[name] = true
+}
+
+func (g *generator) leave(name string) { delete(g.visiting, name) }
+
+func deref(e goast.Expr) goast.Expr {
+	if star, ok := e.(*goast.StarExpr); ok {
+		return star.X
+	}
+	return e
+}
+
+// typeName is pkg.Name or Name of a (pointer to a) named type.
+func typeName(e goast.Expr) string {
+	switch t := deref(e).(type) {
+	case *goast.Ident:
+		return t.Name
+	case *goast.SelectorExpr:
+		if pkg, ok := t.X.(*goast.Ident); ok {
+			return pkg.Name + "." + t.Sel.Name
+		}
+		return t.Sel.Name
+	}
+	return ""
+}
+
+func hasOpt(opts, want string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == want {
+			return true
+		}
+	}
+	return false
+}
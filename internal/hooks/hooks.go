@@ -0,0 +1,172 @@
+package hooks
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/godamri/helix-cli/internal/manifest"
+)
+
+// Context describes the generator run a hook is attached to.
+type Context struct {
+	Command string   // e.g. "init", "new entity"
+	Root    string   // Project root (hooks run here)
+	Files   []string // Rendered (or, for pre_render, planned) files relative to Root
+}
+
+// Result is the outcome of a single hook execution.
+type Result struct {
+	Phase    string
+	Name     string
+	Duration time.Duration
+	Err      error
+}
+
+// Error is returned when a hook fails and is not marked continue_on_error.
+type Error struct {
+	Phase string
+	Name  string
+	Err   error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s hook '%s' failed: %v (re-run with --no-hooks to skip hooks)", e.Phase, e.Name, e.Err)
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Runner executes pack and project hooks in declaration order.
+type Runner struct {
+	Hooks    []manifest.Hook
+	Disabled bool
+	Out      io.Writer
+
+	// OnResult, if set, is called after every executed hook.
+	OnResult func(Result)
+}
+
+// NewRunner merges template-pack hooks (first) with project hooks (second).
+func NewRunner(pack *manifest.Pack, project *manifest.Project, disabled bool) *Runner {
+	var all []manifest.Hook
+	if pack != nil {
+		all = append(all, pack.Hooks...)
+	}
+	if project != nil {
+		all = append(all, project.Hooks...)
+	}
+	return &Runner{Hooks: all, Disabled: disabled, Out: os.Stdout}
+}
+
+// Run executes every hook registered for phase and ctx.Command.
+// The first failing hook aborts, unless it is marked continue_on_error.
+func (r *Runner) Run(phase string, ctx Context) error {
+	matching := r.matching(phase, ctx.Command)
+	if len(matching) == 0 {
+		return nil
+	}
+	if r.Disabled {
+		fmt.Fprintf(r.Out, "hooks: skipping %d %s hook(s) (--no-hooks)\n", len(matching), phase)
+		return nil
+	}
+
+	listFile, err := writeFileList(ctx.Files)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(listFile)
+
+	for _, h := range matching {
+		name := h.Name
+		if name == "" {
+			name = h.Run
+		}
+
+		start := time.Now()
+		c := exec.Command("sh", "-c", h.Run)
+		if info, err := os.Stat(ctx.Root); err == nil && info.IsDir() {
+			c.Dir = ctx.Root // pre_render of 'init' runs before the root exists
+		}
+		c.Stdout = r.Out
+		c.Stderr = os.Stderr
+		c.Env = append(os.Environ(),
+			"HELIX_HOOK_PHASE="+phase,
+			"HELIX_COMMAND="+ctx.Command,
+			"HELIX_PROJECT_ROOT="+ctx.Root,
+			"HELIX_RENDERED_FILES="+strings.Join(ctx.Files, "\n"),
+			"HELIX_RENDERED_FILES_LIST="+listFile,
+		)
+
+		runErr := c.Run()
+		elapsed := time.Since(start).Round(time.Millisecond)
+		if r.OnResult != nil {
+			r.OnResult(Result{Phase: phase, Name: name, Duration: elapsed, Err: runErr})
+		}
+		if runErr == nil {
+			fmt.Fprintf(r.Out, "hook [%s] %s: OK (%s)\n", phase, name, elapsed)
+			continue
+		}
+
+		fmt.Fprintf(r.Out, "hook [%s] %s: FAILED (%s): %v\n", phase, name, elapsed, runErr)
+		if !h.ContinueOnError {
+			return &Error{Phase: phase, Name: name, Err: runErr}
+		}
+	}
+	return nil
+}
+
+func (r *Runner) matching(phase, command string) []manifest.Hook {
+	var out []manifest.Hook
+	for _, h := range r.Hooks {
+		if h.Phase != phase {
+			continue
+		}
+		if len(h.Commands) > 0 && !contains(h.Commands, command) {
+			continue
+		}
+		out = append(out, h)
+	}
+	return out
+}
+
+// RelativeFiles converts absolute or cwd-relative paths into sorted paths relative to root.
+func RelativeFiles(root string, paths []string) []string {
+	absRoot, _ := filepath.Abs(root)
+	out := make([]string, 0, len(paths))
+	for _, p := range paths {
+		abs, _ := filepath.Abs(p)
+		if rel, err := filepath.Rel(absRoot, abs); err == nil {
+			p = rel
+		}
+		out = append(out, filepath.ToSlash(p))
+	}
+	sort.Strings(out)
+	return out
+}
+
+func writeFileList(files []string) (string, error) {
+	f, err := os.CreateTemp("", "helix-hook-files-*.txt")
+	if err != nil {
+		return "", fmt.Errorf("create hook file list: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(strings.Join(files, "\n")); err != nil {
+		return "", fmt.Errorf("write hook file list: %w", err)
+	}
+	return f.Name(), nil
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,236 @@
+package inspect
+
+import (
+	"fmt"
+	goast "go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/godamri/helix-cli/internal/manifest"
+	"golang.org/x/tools/go/packages"
+)
+
+// Report is the shape of a generated service as found in its source.
+type Report struct {
+	Service      string       `json:"service"`
+	Module       string       `json:"module"`
+	Driver       string       `json:"driver"`
+	Entities     []Entity     `json:"entities"`
+	Routes       []Route      `json:"routes"`
+	RPCs         []RPC        `json:"rpcs"`
+	Publishes    []Publish    `json:"publishes"`
+	Consumers    []Consumer   `json:"consumers"`
+	Env          []EnvVar     `json:"env"`
+	Dependencies []Dependency `json:"dependencies"`
+
+	// Warnings are problems loading the source that didn't stop the inspection.
+	Warnings []string `json:"-"`
+}
+
+type Entity struct {
+	Name   string  `json:"name"`
+	Driver string  `json:"driver"`
+	Table  string  `json:"table,omitempty"`
+	Fields []Field `json:"fields"`
+}
+
+type Field struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Column string `json:"column,omitempty"`
+}
+
+type Route struct {
+	Method  string `json:"method"` // "*" for Handle/Mount
+	Path    string `json:"path"`
+	Handler string `json:"handler"`
+	Pos     string `json:"pos"`
+}
+
+type RPC struct {
+	Service  string `json:"service"`
+	Method   string `json:"method"`
+	Request  string `json:"request"`
+	Response string `json:"response"`
+	File     string `json:"file"`
+}
+
+// Publish is an outbox event emitted via publishEvent.
+type Publish struct {
+	Topic string `json:"topic"`
+	Pos   string `json:"pos"`
+}
+
+// Consumer is a messaging.ConsumerConfig or worker.ConsumerSpec literal.
+type Consumer struct {
+	Name       string `json:"name,omitempty"` // ConsumerSpec only
+	Topic      string `json:"topic"`
+	DLQTopic   string `json:"dlq_topic,omitempty"`
+	GroupID    string `json:"group_id,omitempty"`
+	MaxRetries string `json:"max_retries,omitempty"`
+	Pos        string `json:"pos"`
+}
+
+// EnvVar is a config field bound via an envconfig tag.
+type EnvVar struct {
+	Name     string `json:"name"`
+	Field    string `json:"field"`
+	Type     string `json:"type"`
+	Default  string `json:"default,omitempty"`
+	Required bool   `json:"required,omitempty"`
+}
+
+// Dependency is an external system the service talks to, derived from its config.
+type Dependency struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"` // database | cache | broker | telemetry | auth
+	Env  string `json:"env"`
+}
+
+// source is a parsed Go file with its path relative to the project root.
+type source struct {
+	rel  string
+	file *goast.File
+}
+
+// Inspect analyzes the service rooted at root. The manifest is optional.
+func Inspect(root string, project *manifest.Project) (*Report, error) {
+	fset := token.NewFileSet()
+	files, warnings, err := loadSources(fset, root)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Report{
+		Entities:     []Entity{},
+		Routes:       []Route{},
+		RPCs:         []RPC{},
+		Publishes:    []Publish{},
+		Consumers:    []Consumer{},
+		Env:          []EnvVar{},
+		Dependencies: []Dependency{},
+		Warnings:     warnings,
+	}
+	if project != nil {
+		r.Service, r.Module, r.Driver = project.Name, project.Module, project.Driver
+	} else {
+		r.Service = filepath.Base(root)
+	}
+
+	a := &analyzer{fset: fset, report: r}
+	for _, src := range files {
+		a.analyze(src)
+	}
+	a.resolveEntities(project)
+	a.resolvePublishes()
+
+	if r.RPCs, err = parseProtos(root); err != nil {
+		return nil, err
+	}
+	r.Dependencies = dependenciesFromEnv(r.Env)
+
+	sort.SliceStable(r.Routes, func(i, j int) bool { return r.Routes[i].Path < r.Routes[j].Path })
+	return r, nil
+}
+
+// loadSources resolves the module's packages with go/packages and falls back to a
+// plain directory walk when 'go list' is unusable (e.g. dependencies not downloaded),
+// returning why as a warning. Generated ent code is skipped; ent/schema is kept.
+//
+// Inspection is read-only: go.mod and go.sum are never updated, so a module
+// that needs 'go mod tidy' is walked instead.
+func loadSources(fset *token.FileSet, root string) ([]source, []string, error) {
+	var out []source
+	keep := func(path string) bool {
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return false
+		}
+		rel = filepath.ToSlash(rel)
+		return !(strings.HasPrefix(rel, "ent/") && !strings.HasPrefix(rel, "ent/schema/"))
+	}
+
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax,
+		Dir:  root,
+		Fset: fset,
+		Env:  append(os.Environ(), "GOPROXY=off", "GOFLAGS=-mod=readonly"),
+	}
+	var warnings []string
+	pkgs, err := packages.Load(cfg, "./...")
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("go/packages: %v", err))
+	} else {
+		var failed []string
+		packages.Visit(pkgs, nil, func(p *packages.Package) {
+			for _, e := range p.Errors {
+				failed = append(failed, e.Error())
+			}
+			for i, f := range p.Syntax {
+				if i < len(p.CompiledGoFiles) && keep(p.CompiledGoFiles[i]) {
+					rel, _ := filepath.Rel(root, p.CompiledGoFiles[i])
+					out = append(out, source{rel: filepath.ToSlash(rel), file: f})
+				}
+			}
+		})
+		if len(failed) > 0 {
+			warnings = append(warnings, fmt.Sprintf("%d package load error(s), e.g. %s", len(failed), failed[0]))
+		}
+	}
+	if len(out) > 0 {
+		return out, warnings, nil
+	}
+	warnings = append(warnings, "no packages loaded; parsed the .go files directly")
+
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != root && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") || !keep(path) {
+			return nil
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return fmt.Errorf("parse %s: %w", path, err)
+		}
+		rel, _ := filepath.Rel(root, path)
+		out = append(out, source{rel: filepath.ToSlash(rel), file: f})
+		return nil
+	})
+	return out, warnings, err
+}
+
+// dependenciesFromEnv maps well-known config variables to the systems behind them.
+func dependenciesFromEnv(env []EnvVar) []Dependency {
+	known := []Dependency{
+		{Name: "postgres", Kind: "database", Env: "DB_DSN"},
+		{Name: "redis", Kind: "cache", Env: "REDIS_ADDR"},
+		{Name: "kafka", Kind: "broker", Env: "KAFKA_BROKERS"},
+		{Name: "otel-collector", Kind: "telemetry", Env: "OTEL_EXPORTER_OTLP_ENDPOINT"},
+		{Name: "jwks", Kind: "auth", Env: "AUTH_JWKS_URL"},
+	}
+
+	names := make(map[string]bool, len(env))
+	for _, e := range env {
+		names[e.Name] = true
+	}
+	out := []Dependency{}
+	for _, d := range known {
+		if names[d.Env] {
+			out = append(out, d)
+		}
+	}
+	return out
+}
@@ -0,0 +1,59 @@
+package inspect
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	protoService = regexp.MustCompile(`^service\s+(\w+)\s*\{`)
+	protoRPC     = regexp.MustCompile(`^rpc\s+(\w+)\s*\(\s*(stream\s+)?([\w.]+)\s*\)\s*returns\s*\(\s*(stream\s+)?([\w.]+)\s*\)`)
+)
+
+// parseProtos lists the RPCs declared under api/proto. Line based: Helix protos keep
+// one declaration per line, which is all buf lint allows anyway.
+func parseProtos(root string) ([]RPC, error) {
+	dir := filepath.Join(root, "api", "proto")
+	rpcs := []RPC{}
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return rpcs, nil
+	}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".proto") {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, path)
+
+		var service string
+		for _, line := range strings.Split(string(data), "\n") {
+			if i := strings.Index(line, "//"); i >= 0 {
+				line = line[:i]
+			}
+			line = strings.TrimSpace(line)
+
+			if m := protoService.FindStringSubmatch(line); m != nil {
+				service = m[1]
+				continue
+			}
+			if m := protoRPC.FindStringSubmatch(line); m != nil && service != "" {
+				rpcs = append(rpcs, RPC{
+					Service:  service,
+					Method:   m[1],
+					Request:  m[2] + m[3],
+					Response: m[4] + m[5],
+					File:     filepath.ToSlash(rel),
+				})
+			}
+		}
+		return nil
+	})
+	return rpcs, err
+}
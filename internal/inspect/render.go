@@ -0,0 +1,109 @@
+package inspect
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+)
+
+// WriteTable prints the report as aligned, sectioned tables.
+func (r *Report) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Service: %s (%s)\tDriver: %s\n", r.Service, r.Module, r.Driver)
+
+	section(tw, "ENTITIES", "ENTITY\tDRIVER\tTABLE\tFIELD\tTYPE\tCOLUMN")
+	for _, e := range r.Entities {
+		for i, f := range e.Fields {
+			if i == 0 {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", e.Name, e.Driver, e.Table, f.Name, f.Type, f.Column)
+				continue
+			}
+			fmt.Fprintf(tw, "\t\t\t%s\t%s\t%s\n", f.Name, f.Type, f.Column)
+		}
+	}
+
+	section(tw, "HTTP ROUTES", "METHOD\tPATH\tHANDLER\tSOURCE")
+	for _, rt := range r.Routes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", rt.Method, rt.Path, rt.Handler, rt.Pos)
+	}
+
+	section(tw, "GRPC", "SERVICE\tRPC\tREQUEST\tRESPONSE")
+	for _, rpc := range r.RPCs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", rpc.Service, rpc.Method, rpc.Request, rpc.Response)
+	}
+
+	section(tw, "PUBLISHED (OUTBOX)", "TOPIC\tSOURCE")
+	for _, p := range r.Publishes {
+		fmt.Fprintf(tw, "%s\t%s\n", p.Topic, p.Pos)
+	}
+
+	section(tw, "CONSUMERS", "TOPIC\tDLQ\tGROUP\tRETRIES\tSOURCE")
+	for _, c := range r.Consumers {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", c.Topic, c.DLQTopic, c.GroupID, c.MaxRetries, c.Pos)
+	}
+
+	section(tw, "ENVIRONMENT", "VARIABLE\tTYPE\tDEFAULT\tREQUIRED\tFIELD")
+	for _, e := range r.Env {
+		required := ""
+		if e.Required {
+			required = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", e.Name, e.Type, e.Default, required, e.Field)
+	}
+
+	return tw.Flush()
+}
+
+func section(w io.Writer, title, header string) {
+	fmt.Fprintf(w, "\n%s\n%s\n", title, header)
+}
+
+var mermaidUnsafe = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// WriteMermaid prints a flowchart of the service, its infrastructure and its topics.
+func (r *Report) WriteMermaid(w io.Writer) error {
+	var b strings.Builder
+	svc := nodeID("svc", r.Service)
+
+	b.WriteString("flowchart LR\n")
+	fmt.Fprintf(&b, "    %s[\"%s\"]\n", svc, r.Service)
+
+	if len(r.Routes) > 0 {
+		fmt.Fprintf(&b, "    http([\"HTTP: %d routes\"]) --> %s\n", len(r.Routes), svc)
+	}
+	if len(r.RPCs) > 0 {
+		fmt.Fprintf(&b, "    grpc([\"gRPC: %d RPCs\"]) --> %s\n", len(r.RPCs), svc)
+	}
+	for _, d := range r.Dependencies {
+		fmt.Fprintf(&b, "    %s --> %s[(\"%s\")]\n", svc, nodeID("dep", d.Name), d.Name)
+	}
+
+	seen := map[string]bool{}
+	topic := func(name string) string {
+		id := nodeID("topic", name)
+		if !seen[id] {
+			seen[id] = true
+			fmt.Fprintf(&b, "    %s>\"%s\"]\n", id, name)
+		}
+		return id
+	}
+	for _, p := range r.Publishes {
+		fmt.Fprintf(&b, "    %s -- publish --> %s\n", svc, topic(p.Topic))
+	}
+	for _, c := range r.Consumers {
+		fmt.Fprintf(&b, "    %s -- consume --> %s\n", topic(c.Topic), svc)
+		if c.DLQTopic != "" {
+			fmt.Fprintf(&b, "    %s -. dlq .-> %s\n", svc, topic(c.DLQTopic))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func nodeID(kind, name string) string {
+	return kind + "_" + mermaidUnsafe.ReplaceAllString(name, "_")
+}
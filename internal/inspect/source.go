@@ -0,0 +1,427 @@
+package inspect
+
+import (
+	"fmt"
+	goast "go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/godamri/helix-cli/internal/events"
+	"github.com/godamri/helix-cli/internal/manifest"
+)
+
+// chi router methods whose first argument is a path pattern.
+var routeMethods = map[string]string{
+	"Get": "GET", "Post": "POST", "Put": "PUT", "Patch": "PATCH", "Delete": "DELETE",
+	"Head": "HEAD", "Options": "OPTIONS", "Connect": "CONNECT", "Trace": "TRACE",
+	"Handle": "*", "HandleFunc": "*", "Mount": "*",
+}
+
+type schemaInfo struct {
+	table   string
+	columns map[string]string // column -> ent field kind
+}
+
+type analyzer struct {
+	fset   *token.FileSet
+	report *Report
+	rel    string // file being analyzed
+
+	structs map[string][]Field // internal/core/entity structs
+	errors  map[string]bool    // entity package types with an Error() method
+	schemas map[string]*schemaInfo
+
+	eventFiles  []*goast.File  // internal/core/event
+	eventTopics map[int]string // Publishes index -> event type, resolved once all files are read
+}
+
+func (a *analyzer) analyze(src source) {
+	if a.structs == nil {
+		a.structs = map[string][]Field{}
+		a.errors = map[string]bool{}
+		a.schemas = map[string]*schemaInfo{}
+		a.eventTopics = map[int]string{}
+	}
+
+	a.rel = src.rel
+
+	switch {
+	case strings.HasPrefix(src.rel, "internal/core/entity/"):
+		a.collectEntities(src.file)
+	case strings.HasPrefix(src.rel, "ent/schema/"):
+		a.collectSchemas(src.file)
+	case strings.HasPrefix(src.rel, events.Dir+"/"):
+		a.eventFiles = append(a.eventFiles, src.file)
+	}
+
+	for _, decl := range src.file.Decls {
+		if fn, ok := decl.(*goast.FuncDecl); ok && fn.Body != nil {
+			a.walkRoutes(fn.Body, "")
+		}
+	}
+
+	goast.Inspect(src.file, func(n goast.Node) bool {
+		switch n := n.(type) {
+		case *goast.FuncDecl:
+			// ConsumerSpec methods build configs from the spec, not from literals.
+			return receiverName(n) != "ConsumerSpec"
+		case *goast.CallExpr:
+			a.publishCall(n)
+		case *goast.CompositeLit:
+			a.consumerConfig(n)
+		case *goast.TypeSpec:
+			if st, ok := n.Type.(*goast.StructType); ok {
+				a.envFields(st, "")
+			}
+		}
+		return true
+	})
+}
+
+func (a *analyzer) pos(p token.Pos) string {
+	return fmt.Sprintf("%s:%d", a.rel, a.fset.Position(p).Line)
+}
+
+// --- Entities -----------------------------------------------------------------
+
+func (a *analyzer) collectEntities(f *goast.File) {
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *goast.FuncDecl:
+			if d.Recv != nil && d.Name.Name == "Error" {
+				a.errors[receiverName(d)] = true
+			}
+		case *goast.GenDecl:
+			for _, spec := range d.Specs {
+				ts, ok := spec.(*goast.TypeSpec)
+				if !ok || !ts.Name.IsExported() {
+					continue
+				}
+				st, ok := ts.Type.(*goast.StructType)
+				if !ok {
+					continue
+				}
+				fields := []Field{}
+				for _, fl := range st.Fields.List {
+					for _, name := range fl.Names {
+						fields = append(fields, Field{Name: name.Name, Type: types.ExprString(fl.Type)})
+					}
+				}
+				a.structs[ts.Name.Name] = fields
+			}
+		}
+	}
+}
+
+// collectSchemas reads columns from ent Fields() methods and the table name from Annotations().
+func (a *analyzer) collectSchemas(f *goast.File) {
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*goast.FuncDecl)
+		if !ok || fn.Recv == nil || fn.Body == nil {
+			continue
+		}
+		name := receiverName(fn)
+		info := a.schemas[name]
+		if info == nil {
+			info = &schemaInfo{columns: map[string]string{}}
+			a.schemas[name] = info
+		}
+
+		goast.Inspect(fn.Body, func(n goast.Node) bool {
+			switch n := n.(type) {
+			case *goast.CallExpr:
+				sel, ok := n.Fun.(*goast.SelectorExpr)
+				if !ok || fn.Name.Name != "Fields" || len(n.Args) == 0 {
+					return true
+				}
+				if pkg, ok := sel.X.(*goast.Ident); ok && pkg.Name == "field" {
+					if col, ok := stringLit(n.Args[0]); ok {
+						info.columns[col] = strings.ToLower(sel.Sel.Name)
+					}
+				}
+			case *goast.KeyValueExpr:
+				if key, ok := n.Key.(*goast.Ident); ok && key.Name == "Table" && fn.Name.Name == "Annotations" {
+					if table, ok := stringLit(n.Value); ok {
+						info.table = table
+					}
+				}
+			}
+			return true
+		})
+	}
+}
+
+func (a *analyzer) resolveEntities(project *manifest.Project) {
+	drivers := map[string]string{}
+	if project != nil {
+		for _, e := range project.Entities {
+			drivers[e.Name] = e.Driver
+		}
+	}
+
+	names := make([]string, 0, len(a.structs))
+	for name := range a.structs {
+		if a.errors[name] {
+			continue
+		}
+		if len(drivers) > 0 {
+			if _, ok := drivers[name]; !ok {
+				continue
+			}
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		e := Entity{Name: name, Driver: drivers[name], Fields: a.structs[name]}
+		if e.Driver == "" {
+			e.Driver = a.report.Driver
+		}
+		if info := a.schemas[name]; info != nil {
+			e.Table = info.table
+			for i, fl := range e.Fields {
+				if col := toSnake(fl.Name); info.columns[col] != "" {
+					e.Fields[i].Column = col
+				}
+			}
+		}
+		a.report.Entities = append(a.report.Entities, e)
+	}
+}
+
+// --- HTTP routes --------------------------------------------------------------
+
+// walkRoutes finds chi registrations, following r.Route/r.Group closures to build full paths.
+func (a *analyzer) walkRoutes(node goast.Node, prefix string) {
+	goast.Inspect(node, func(n goast.Node) bool {
+		call, ok := n.(*goast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*goast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		switch sel.Sel.Name {
+		case "Route":
+			if len(call.Args) != 2 {
+				return true
+			}
+			path, ok := stringLit(call.Args[0])
+			body, isFunc := call.Args[1].(*goast.FuncLit)
+			if !ok || !isFunc {
+				return true
+			}
+			a.walkRoutes(body.Body, joinPath(prefix, path))
+			return false
+		case "Group":
+			if len(call.Args) == 1 {
+				if body, ok := call.Args[0].(*goast.FuncLit); ok {
+					a.walkRoutes(body.Body, prefix)
+					return false
+				}
+			}
+			return true
+		}
+
+		method, ok := routeMethods[sel.Sel.Name]
+		if !ok || len(call.Args) < 2 {
+			return true
+		}
+		path, ok := stringLit(call.Args[0])
+		if !ok || !strings.HasPrefix(path, "/") {
+			return true
+		}
+		a.report.Routes = append(a.report.Routes, Route{
+			Method:  method,
+			Path:    joinPath(prefix, path),
+			Handler: types.ExprString(call.Args[1]),
+			Pos:     a.pos(call.Pos()),
+		})
+		return true
+	})
+}
+
+func joinPath(prefix, path string) string {
+	if prefix == "" {
+		return path
+	}
+	return strings.TrimSuffix(prefix, "/") + path
+}
+
+// --- Messaging ----------------------------------------------------------------
+
+func (a *analyzer) publishCall(call *goast.CallExpr) {
+	var name string
+	switch fn := call.Fun.(type) {
+	case *goast.SelectorExpr:
+		name = fn.Sel.Name
+	case *goast.Ident:
+		name = fn.Name
+	}
+	if name != "publishEvent" || len(call.Args) < 2 {
+		return
+	}
+	topic := exprValue(call.Args[1])
+	// Typed contracts: publishEvent(ctx, event.OrderCreated{...}).
+	if lit, ok := call.Args[1].(*goast.CompositeLit); ok && lit.Type != nil {
+		topic = types.ExprString(lit.Type)
+		if sel, ok := lit.Type.(*goast.SelectorExpr); ok {
+			a.eventTopics[len(a.report.Publishes)] = sel.Sel.Name
+		}
+	}
+	a.report.Publishes = append(a.report.Publishes, Publish{Topic: topic, Pos: a.pos(call.Pos())})
+}
+
+// resolvePublishes replaces event struct literals with the topic of their contract.
+func (a *analyzer) resolvePublishes() {
+	topics := events.Topics(a.eventFiles)
+	for i, typ := range a.eventTopics {
+		if topic, ok := topics[typ]; ok {
+			a.report.Publishes[i].Topic = topic
+		}
+	}
+}
+
+func (a *analyzer) consumerConfig(lit *goast.CompositeLit) {
+	if arr, ok := lit.Type.(*goast.ArrayType); ok && isConsumerType(arr.Elt) {
+		// Elements of []worker.ConsumerSpec{...} elide their type.
+		for _, elt := range lit.Elts {
+			if el, ok := elt.(*goast.CompositeLit); ok && el.Type == nil {
+				a.consumer(el)
+			}
+		}
+		return
+	}
+	if isConsumerType(lit.Type) {
+		a.consumer(lit)
+	}
+}
+
+func isConsumerType(e goast.Expr) bool {
+	var name string
+	switch t := e.(type) {
+	case *goast.SelectorExpr:
+		name = t.Sel.Name
+	case *goast.Ident:
+		name = t.Name
+	}
+	return name == "ConsumerConfig" || name == "ConsumerSpec"
+}
+
+func (a *analyzer) consumer(lit *goast.CompositeLit) {
+	c := Consumer{Pos: a.pos(lit.Pos())}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*goast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := kv.Key.(*goast.Ident)
+		if !ok {
+			continue
+		}
+		switch key.Name {
+		case "Name":
+			c.Name = exprValue(kv.Value)
+		case "Topic":
+			c.Topic = exprValue(kv.Value)
+		case "DLQTopic":
+			c.DLQTopic = exprValue(kv.Value)
+		case "GroupID":
+			c.GroupID = exprValue(kv.Value)
+		case "MaxRetries":
+			c.MaxRetries = exprValue(kv.Value)
+		}
+	}
+	a.report.Consumers = append(a.report.Consumers, c)
+}
+
+// --- Config -------------------------------------------------------------------
+
+// envFields records envconfig-tagged fields, recursing into untagged nested structs.
+func (a *analyzer) envFields(st *goast.StructType, path string) {
+	for _, fl := range st.Fields.List {
+		if len(fl.Names) == 0 {
+			continue
+		}
+		fieldPath := path + fl.Names[0].Name
+
+		var tag reflect.StructTag
+		if fl.Tag != nil {
+			raw, _ := strconv.Unquote(fl.Tag.Value)
+			tag = reflect.StructTag(raw)
+		}
+
+		name, ok := tag.Lookup("envconfig")
+		if !ok {
+			if nested, isStruct := fl.Type.(*goast.StructType); isStruct {
+				a.envFields(nested, fieldPath+".")
+			}
+			continue
+		}
+		a.report.Env = append(a.report.Env, EnvVar{
+			Name:     name,
+			Field:    fieldPath,
+			Type:     types.ExprString(fl.Type),
+			Default:  tag.Get("default"),
+			Required: tag.Get("required") == "true",
+		})
+	}
+}
+
+// --- Helpers ------------------------------------------------------------------
+
+func receiverName(fn *goast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	t := fn.Recv.List[0].Type
+	if star, ok := t.(*goast.StarExpr); ok {
+		t = star.X
+	}
+	if id, ok := t.(*goast.Ident); ok {
+		return id.Name
+	}
+	return ""
+}
+
+func stringLit(e goast.Expr) (string, bool) {
+	lit, ok := e.(*goast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	return s, err == nil
+}
+
+// exprValue returns a string literal's value, or the expression source otherwise.
+func exprValue(e goast.Expr) string {
+	if s, ok := stringLit(e); ok {
+		return s
+	}
+	return types.ExprString(e)
+}
+
+// toSnake converts a Go field name to ent's column naming: CreatedAt -> created_at, ID -> id.
+func toSnake(s string) string {
+	runes := []rune(s)
+	var sb strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prevLower := unicode.IsLower(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (nextLower && unicode.IsUpper(runes[i-1])) {
+				sb.WriteByte('_')
+			}
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
@@ -0,0 +1,61 @@
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// PackFile is the template-pack manifest, resolved through the template fetcher
+// so a pack in ~/.helix/templates can ship its own hooks.
+const PackFile = "templates/helix-pack.json"
+
+// Hook phases.
+const (
+	PhasePreRender  = "pre_render"  // Before any template is written. Files lists the planned outputs.
+	PhasePostRender = "post_render" // After templates are written, before dependency/wiring steps.
+	PhasePostWire   = "post_wire"   // After go mod/generate and main.go wiring.
+)
+
+// Hook is a shell command run at a given phase of a generator command.
+type Hook struct {
+	Name  string `json:"name"`
+	Phase string `json:"phase"`
+	Run   string `json:"run"`
+
+	// Commands limits the hook to e.g. "init", "new entity". Empty means every generator.
+	Commands []string `json:"commands,omitempty"`
+
+	// ContinueOnError reports the failure but doesn't abort the command.
+	ContinueOnError bool `json:"continue_on_error,omitempty"`
+}
+
+// Pack describes a template pack.
+type Pack struct {
+	Name  string `json:"name"`
+	Hooks []Hook `json:"hooks"`
+}
+
+// PackReader is the subset of template.Fetcher needed to load a pack manifest.
+type PackReader interface {
+	ReadFile(path string) ([]byte, error)
+}
+
+// LoadPack reads the template-pack manifest. A pack without one is valid and has no hooks.
+func LoadPack(r PackReader) (*Pack, error) {
+	data, err := r.ReadFile(PackFile)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, os.ErrNotExist) {
+			return &Pack{}, nil
+		}
+		return nil, fmt.Errorf("read template pack manifest: %w", err)
+	}
+
+	var p Pack
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parse template pack manifest: %w", err)
+	}
+	return &p, nil
+}
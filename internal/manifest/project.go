@@ -0,0 +1,164 @@
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ProjectFile is the per-service manifest written by 'init' and kept up to date by 'new'.
+const ProjectFile = ".helix/manifest.json"
+
+// ProjectPackDir is the root of a project's template overrides. It mirrors the
+// pack layout, so overrides live in .helix/templates/...
+const ProjectPackDir = ".helix"
+
+// ErrNoProject is returned when no project manifest can be found.
+var ErrNoProject = errors.New("not inside a helix project (no " + ProjectFile + " found)")
+
+// Entity is a domain entity scaffolded in the project.
+type Entity struct {
+	Name   string `json:"name"`
+	Driver string `json:"driver"`
+}
+
+// Consumer is a topic subscription of the service. Its handler lives in
+// internal/adapter/worker/consumer_<name>.go; consumers_gen.go is rendered
+// from these entries and registers every one at startup.
+type Consumer struct {
+	Name           string `json:"name"`
+	Topic          string `json:"topic"`
+	Event          string `json:"event,omitempty"` // Contract type in internal/core/event, if the service owns it
+	Group          string `json:"group,omitempty"` // Defaults to <SERVICE_NAME>-<name>
+	MaxRetries     int    `json:"max_retries"`
+	InitialBackoff string `json:"initial_backoff"` // Go duration, e.g. "1s"
+	MaxBackoff     string `json:"max_backoff"`
+	DLQ            string `json:"dlq,omitempty"` // Empty disables dead-lettering
+	Concurrency    int    `json:"concurrency"`
+	HashIDs        bool   `json:"hash_ids,omitempty"` // Messages have no CloudEvents envelope: dedup on a hash of key and payload
+}
+
+// Job is a scheduled job of the service. Its handler lives in
+// internal/adapter/worker/job_<name>.go; jobs_gen.go is rendered from these
+// entries and the worker's scheduler runs every one.
+type Job struct {
+	Name     string `json:"name"`
+	Schedule string `json:"schedule"` // Five-field cron expression, in UTC
+	Timeout  string `json:"timeout"`  // Go duration, e.g. "5m"
+}
+
+// FileSource is the origin of a generated file.
+type FileSource struct {
+	Template string `json:"template"`
+	Source   string `json:"source"` // template-dir | project | user | embedded | plugin:<name>
+}
+
+// Project is the machine-readable description of a generated service.
+// It is the contract handed to plugins, so fields are only ever added, never renamed.
+type Project struct {
+	Name     string   `json:"name"`
+	Module   string   `json:"module"`
+	Driver   string   `json:"driver"`
+	Broker   string   `json:"broker,omitempty"`
+	Entities []Entity `json:"entities"`
+	Hooks    []Hook   `json:"hooks,omitempty"`
+
+	Consumers []Consumer `json:"consumers,omitempty"`
+	Jobs      []Job      `json:"jobs,omitempty"`
+
+	// Files records which template (and override source) produced each generated
+	// file, keyed by slash path relative to Root.
+	Files map[string]FileSource `json:"files,omitempty"`
+
+	// Root is the absolute project directory. Not persisted.
+	Root string `json:"-"`
+}
+
+// FindProject walks up from dir until it finds a project manifest.
+func FindProject(dir string) (*Project, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if _, err := os.Stat(filepath.Join(abs, ProjectFile)); err == nil {
+			return LoadProject(abs)
+		}
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return nil, ErrNoProject
+		}
+		abs = parent
+	}
+}
+
+// LoadProject reads the project manifest located in root.
+func LoadProject(root string) (*Project, error) {
+	data, err := os.ReadFile(filepath.Join(root, ProjectFile))
+	if err != nil {
+		return nil, fmt.Errorf("read project manifest: %w", err)
+	}
+
+	var p Project
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parse project manifest: %w", err)
+	}
+	p.Root = root
+	return &p, nil
+}
+
+// Save writes the manifest back to <Root>/.helix/manifest.json.
+func (p *Project) Save() error {
+	return writeJSON(filepath.Join(p.Root, ProjectFile), p)
+}
+
+// AddEntity registers an entity, replacing any previous entry with the same name.
+func (p *Project) AddEntity(e Entity) {
+	for i, existing := range p.Entities {
+		if existing.Name == e.Name {
+			p.Entities[i] = e
+			return
+		}
+	}
+	p.Entities = append(p.Entities, e)
+}
+
+// AddConsumer registers a consumer, replacing any previous entry with the same name.
+func (p *Project) AddConsumer(c Consumer) {
+	for i, existing := range p.Consumers {
+		if existing.Name == c.Name {
+			p.Consumers[i] = c
+			return
+		}
+	}
+	p.Consumers = append(p.Consumers, c)
+}
+
+// AddJob registers a job, replacing any previous entry with the same name.
+func (p *Project) AddJob(j Job) {
+	for i, existing := range p.Jobs {
+		if existing.Name == j.Name {
+			p.Jobs[i] = j
+			return
+		}
+	}
+	p.Jobs = append(p.Jobs, j)
+}
+
+// RecordFile notes the template and source that rendered path (absolute or relative to Root).
+func (p *Project) RecordFile(path, template, source string) {
+	if filepath.IsAbs(path) {
+		rel, err := filepath.Rel(p.Root, path)
+		if err != nil {
+			return
+		}
+		path = rel
+	}
+	if p.Files == nil {
+		p.Files = make(map[string]FileSource)
+	}
+	p.Files[filepath.ToSlash(path)] = FileSource{Template: template, Source: source}
+}
@@ -0,0 +1,93 @@
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// WorkspaceFile is the marker file that turns a directory into a Helix workspace.
+const WorkspaceFile = ".helix/workspace.json"
+
+// ErrNoWorkspace is returned when no workspace root can be found.
+var ErrNoWorkspace = errors.New("not inside a helix workspace (no " + WorkspaceFile + " found)")
+
+// Service is a single service registered in a workspace.
+type Service struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"` // Relative to the workspace root
+	Module string `json:"module"`
+	Driver string `json:"driver"`
+}
+
+// Workspace describes a go.work based monorepo with shared infra and protos.
+type Workspace struct {
+	Name     string    `json:"name"`
+	Services []Service `json:"services"`
+
+	// Root is the absolute workspace directory. Not persisted.
+	Root string `json:"-"`
+}
+
+// FindWorkspace walks up from dir until it finds a workspace marker.
+func FindWorkspace(dir string) (*Workspace, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if _, err := os.Stat(filepath.Join(abs, WorkspaceFile)); err == nil {
+			return LoadWorkspace(abs)
+		}
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return nil, ErrNoWorkspace
+		}
+		abs = parent
+	}
+}
+
+// LoadWorkspace reads the workspace manifest located in root.
+func LoadWorkspace(root string) (*Workspace, error) {
+	data, err := os.ReadFile(filepath.Join(root, WorkspaceFile))
+	if err != nil {
+		return nil, fmt.Errorf("read workspace manifest: %w", err)
+	}
+
+	var ws Workspace
+	if err := json.Unmarshal(data, &ws); err != nil {
+		return nil, fmt.Errorf("parse workspace manifest: %w", err)
+	}
+	ws.Root = root
+	return &ws, nil
+}
+
+// Save writes the manifest back to <Root>/.helix/workspace.json.
+func (w *Workspace) Save() error {
+	return writeJSON(filepath.Join(w.Root, WorkspaceFile), w)
+}
+
+// AddService registers a service, replacing any previous entry with the same path.
+func (w *Workspace) AddService(svc Service) {
+	for i, s := range w.Services {
+		if s.Path == svc.Path {
+			w.Services[i] = svc
+			return
+		}
+	}
+	w.Services = append(w.Services, svc)
+}
+
+func writeJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create dir: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
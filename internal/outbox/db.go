@@ -0,0 +1,159 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	_ "github.com/jackc/pgx/v5/stdlib" // database/sql driver "pgx"
+)
+
+// The queries mirror templates/app/internal/adapter/repository/outbox_admin_repository.go.tmpl;
+// keep the two in sync.
+
+const eventColumns = `id::text, topic, key, status, convert_from(payload, 'UTF8'), headers, retry_count, next_retry, COALESCE(last_error, ''), created_at, updated_at, processed_at`
+
+var (
+	requeueFrom = []string{StatusFailed, StatusSkipped, StatusProcessed}
+	skipFrom    = []string{StatusPending, StatusFailed}
+)
+
+// DBClient works on the outboxes table directly, for when the service is down
+// or runs without the operator API.
+type DBClient struct {
+	db *sql.DB
+}
+
+// OpenDB connects to the service database at dsn.
+func OpenDB(ctx context.Context, dsn string) (*DBClient, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &DBClient{db: db}, nil
+}
+
+func (c *DBClient) List(ctx context.Context, f Filter) ([]Event, error) {
+	where, args := whereClause(f)
+	limit := f.Limit
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	args = append(args, limit)
+	query := fmt.Sprintf(`SELECT %s FROM outboxes %s ORDER BY created_at DESC LIMIT $%d`, eventColumns, where, len(args))
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []Event{}
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, *e)
+	}
+	return events, rows.Err()
+}
+
+func (c *DBClient) Get(ctx context.Context, id string) (*Event, error) {
+	e, err := scanEvent(c.db.QueryRowContext(ctx, `SELECT `+eventColumns+` FROM outboxes WHERE id::text = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("outbox event %s not found", id)
+	}
+	return e, err
+}
+
+func (c *DBClient) Requeue(ctx context.Context, f Filter) (int64, error) {
+	return c.update(ctx, f, requeueFrom,
+		`status = 'PENDING', retry_count = 0, next_retry = NOW(), last_error = NULL, processed_at = NULL, updated_at = NOW()`)
+}
+
+func (c *DBClient) Skip(ctx context.Context, f Filter) (int64, error) {
+	return c.update(ctx, f, skipFrom,
+		`status = 'SKIPPED', last_error = 'skipped_by_operator', processed_at = NOW(), updated_at = NOW()`)
+}
+
+func (c *DBClient) Close() error { return c.db.Close() }
+
+func (c *DBClient) update(ctx context.Context, f Filter, from []string, set string) (int64, error) {
+	if f.Empty() {
+		return 0, errors.New("a filter is required")
+	}
+	if err := checkStatus(f.Status, from); err != nil {
+		return 0, err
+	}
+	where, args := whereClause(f)
+	args = append(args, from)
+	res, err := c.db.ExecContext(ctx, fmt.Sprintf(`UPDATE outboxes SET %s %s AND status = ANY($%d)`, set, where, len(args)), args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// checkStatus rejects a status filter the action can never match.
+func checkStatus(status string, allowed []string) error {
+	if status == "" {
+		return nil
+	}
+	for _, s := range allowed {
+		if s == status {
+			return nil
+		}
+	}
+	return fmt.Errorf("status must be one of %s", strings.Join(allowed, ", "))
+}
+
+func whereClause(f Filter) (string, []interface{}) {
+	conds := []string{"TRUE"}
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if len(f.IDs) > 0 {
+		add("id::text = ANY($%d)", f.IDs)
+	}
+	if f.Topic != "" {
+		add("topic = $%d", f.Topic)
+	}
+	if f.Status != "" {
+		add("status = $%d", f.Status)
+	}
+	if f.From != nil {
+		add("created_at >= $%d", *f.From)
+	}
+	if f.To != nil {
+		add("created_at < $%d", *f.To)
+	}
+	return "WHERE " + strings.Join(conds, " AND "), args
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanEvent(row scanner) (*Event, error) {
+	var e Event
+	var headers []byte
+	err := row.Scan(&e.ID, &e.Topic, &e.Key, &e.Status, &e.Payload, &headers,
+		&e.RetryCount, &e.NextRetry, &e.LastError, &e.CreatedAt, &e.UpdatedAt, &e.ProcessedAt)
+	if err != nil {
+		return nil, err
+	}
+	if len(headers) > 0 {
+		_ = json.Unmarshal(headers, &e.Headers)
+	}
+	return &e, nil
+}
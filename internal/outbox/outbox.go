@@ -0,0 +1,59 @@
+// Package outbox operates on a service's transactional outbox, either through
+// the service's operator API (/admin/outbox) or directly in Postgres.
+package outbox
+
+import (
+	"context"
+	"time"
+)
+
+// Statuses of an outbox row.
+const (
+	StatusPending    = "PENDING"
+	StatusProcessing = "PROCESSING"
+	StatusProcessed  = "PROCESSED"
+	StatusFailed     = "FAILED"
+	StatusSkipped    = "SKIPPED"
+)
+
+// Event mirrors dto.OutboxEventResponse of the generated service.
+type Event struct {
+	ID          string            `json:"id"`
+	Topic       string            `json:"topic"`
+	Key         string            `json:"key,omitempty"`
+	Status      string            `json:"status"`
+	Payload     string            `json:"payload"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	RetryCount  int               `json:"retry_count"`
+	NextRetry   time.Time         `json:"next_retry"`
+	LastError   string            `json:"last_error,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	ProcessedAt *time.Time        `json:"processed_at,omitempty"`
+}
+
+// Filter mirrors dto.OutboxFilterRequest. Zero fields match everything.
+type Filter struct {
+	IDs    []string   `json:"ids,omitempty"`
+	Topic  string     `json:"topic,omitempty"`
+	Status string     `json:"status,omitempty"`
+	From   *time.Time `json:"from,omitempty"`
+	To     *time.Time `json:"to,omitempty"`
+	Limit  int        `json:"-"` // List only
+}
+
+// Empty reports whether f selects every row. Bulk actions refuse it.
+func (f Filter) Empty() bool {
+	return len(f.IDs) == 0 && f.Topic == "" && f.Status == "" && f.From == nil && f.To == nil
+}
+
+// Client is implemented by the API and the direct-database backends.
+type Client interface {
+	List(ctx context.Context, f Filter) ([]Event, error)
+	Get(ctx context.Context, id string) (*Event, error)
+	// Requeue resets FAILED, SKIPPED or PROCESSED rows to PENDING with a fresh retry budget.
+	Requeue(ctx context.Context, f Filter) (int64, error)
+	// Skip marks PENDING or FAILED rows SKIPPED.
+	Skip(ctx context.Context, f Filter) (int64, error)
+	Close() error
+}
@@ -0,0 +1,159 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Stable error codes. Tools match on these, so never rename one; add new ones instead.
+const (
+	CodeInvalidArgument = "E_INVALID_ARGUMENT" // Bad flags/args, reserved names
+	CodePromptRequired  = "E_PROMPT_REQUIRED"  // Interactive input needed; pass it as a flag
+	CodeAlreadyExists   = "E_ALREADY_EXISTS"   // Target file/dir exists
+	CodeNotFound        = "E_NOT_FOUND"        // Project, workspace, file or template missing
+	CodeTemplate        = "E_TEMPLATE"         // Template read/parse/execute failure
+	CodeHookFailed      = "E_HOOK_FAILED"      // A pre/post generation hook failed
+	CodePlugin          = "E_PLUGIN"           // External plugin failed or broke the protocol
+	CodeCommandFailed   = "E_COMMAND_FAILED"   // An external tool (go, git, docker, make) failed
+	CodeIO              = "E_IO"               // Filesystem errors
+	CodeRemote          = "E_REMOTE"           // A service API or database call failed
+	CodeIncompatible    = "E_INCOMPATIBLE"     // Event schemas stale or broken against a release
+	CodeUnknownCommand  = "E_UNKNOWN_COMMAND"
+	CodeInternal        = "E_INTERNAL" // Anything not classified above
+)
+
+// Error attaches a stable code to an error.
+type Error struct {
+	Code string
+	Err  error
+}
+
+func (e *Error) Error() string { return e.Err.Error() }
+func (e *Error) Unwrap() error { return e.Err }
+
+// Errorf creates a coded error.
+func Errorf(code, format string, args ...interface{}) error {
+	return &Error{Code: code, Err: fmt.Errorf(format, args...)}
+}
+
+// Wrap attaches a code to err. A nil err stays nil, an already coded err keeps its code.
+func Wrap(code string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var coded *Error
+	if errors.As(err, &coded) {
+		return err
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// ErrorInfo is the JSON shape of a failure.
+type ErrorInfo struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Wiring tells integrations what was wired automatically and what still needs a human.
+type Wiring struct {
+	Applied []string `json:"applied"`
+	Pending []string `json:"pending"`
+}
+
+// HookResult is the outcome of a single generation hook.
+type HookResult struct {
+	Phase    string `json:"phase"`
+	Name     string `json:"name"`
+	OK       bool   `json:"ok"`
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Result is the single JSON document printed by every command in --output json mode.
+type Result struct {
+	Command       string       `json:"command"`
+	Status        string       `json:"status"` // ok | error
+	FilesCreated  []string     `json:"files_created"`
+	FilesModified []string     `json:"files_modified"`
+	Wiring        Wiring       `json:"wiring"`
+	Hooks         []HookResult `json:"hooks"`
+	Warnings      []string     `json:"warnings"`
+	NextSteps     []string     `json:"next_steps"`
+	Messages      []string     `json:"messages"`
+	Data          interface{}  `json:"data,omitempty"`
+	Error         *ErrorInfo   `json:"error,omitempty"`
+}
+
+// NewResult returns an empty result with non-nil slices so JSON consumers never see null lists.
+func NewResult() *Result {
+	return &Result{
+		Status:        "ok",
+		FilesCreated:  []string{},
+		FilesModified: []string{},
+		Wiring:        Wiring{Applied: []string{}, Pending: []string{}},
+		Hooks:         []HookResult{},
+		Warnings:      []string{},
+		NextSteps:     []string{},
+		Messages:      []string{},
+	}
+}
+
+func (r *Result) Created(path string) { r.FilesCreated = appendUnique(r.FilesCreated, relPath(path)) }
+func (r *Result) Modified(path string) {
+	r.FilesModified = appendUnique(r.FilesModified, relPath(path))
+}
+func (r *Result) Warn(msg string)     { r.Warnings = append(r.Warnings, msg) }
+func (r *Result) Next(step string)    { r.NextSteps = append(r.NextSteps, step) }
+func (r *Result) Message(msg string)  { r.Messages = append(r.Messages, msg) }
+func (r *Result) Applied(desc string) { r.Wiring.Applied = append(r.Wiring.Applied, desc) }
+func (r *Result) Pending(code string) { r.Wiring.Pending = append(r.Wiring.Pending, code) }
+
+// Fail records err with its code (CodeInternal when unclassified).
+func (r *Result) Fail(code string, err error) {
+	r.Status = "error"
+	r.Error = &ErrorInfo{Code: code, Message: err.Error()}
+}
+
+// Write prints the result as indented JSON.
+func (r *Result) Write(w io.Writer) error {
+	sort.Strings(r.FilesCreated)
+	sort.Strings(r.FilesModified)
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// CodeOf extracts the stable code from err, or "" when it carries none.
+func CodeOf(err error) string {
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	return ""
+}
+
+func relPath(path string) string {
+	if filepath.IsAbs(path) {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, path); err == nil {
+				path = rel
+			}
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
+
+func appendUnique(list []string, v string) []string {
+	for _, s := range list {
+		if s == v {
+			return list
+		}
+	}
+	return append(list, v)
+}
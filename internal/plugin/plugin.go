@@ -0,0 +1,60 @@
+package plugin
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Prefix is the executable name prefix used for git-style plugin discovery.
+// 'helix-cli foo' runs helix-cli-foo, 'helix-cli new foo' runs helix-cli-new-foo.
+const Prefix = "helix-cli-"
+
+// Plugin is an external executable found on PATH.
+type Plugin struct {
+	Name string `json:"name"` // Command name without prefix, e.g. "new-feature-flag"
+	Path string `json:"path"`
+}
+
+// Lookup resolves a plugin by name (without prefix).
+func Lookup(name string) (*Plugin, bool) {
+	path, err := exec.LookPath(Prefix + name)
+	if err != nil {
+		return nil, false
+	}
+	return &Plugin{Name: name, Path: path}, true
+}
+
+// Discover lists every helix-cli-* executable on PATH.
+// Earlier PATH entries win, mirroring how the shell resolves commands.
+func Discover() []Plugin {
+	seen := make(map[string]bool)
+	var found []Plugin
+
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if e.IsDir() || !strings.HasPrefix(e.Name(), Prefix) {
+				continue
+			}
+			name := strings.TrimSuffix(strings.TrimPrefix(e.Name(), Prefix), ".exe")
+			if name == "" || seen[name] {
+				continue
+			}
+			info, err := e.Info()
+			if err != nil || info.Mode()&0111 == 0 {
+				continue
+			}
+			seen[name] = true
+			found = append(found, Plugin{Name: name, Path: filepath.Join(dir, e.Name())})
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool { return found[i].Name < found[j].Name })
+	return found
+}
@@ -0,0 +1,172 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	helixAst "github.com/godamri/helix-cli/internal/ast"
+	"github.com/godamri/helix-cli/internal/manifest"
+	helixTemplate "github.com/godamri/helix-cli/internal/template"
+)
+
+// ProtocolVersion is bumped only on breaking changes to Request/Response.
+const ProtocolVersion = 1
+
+// MainFile is the only file plugins may inject into.
+const MainFile = "cmd/server/main.go"
+
+// Request is written as JSON to the plugin's stdin.
+type Request struct {
+	ProtocolVersion int                        `json:"protocol_version"`
+	Command         string                     `json:"command"`
+	Args            []string                   `json:"args"`
+	Cwd             string                     `json:"cwd"`
+	ProjectRoot     string                     `json:"project_root,omitempty"`
+	Manifest        *manifest.Project          `json:"manifest"`
+	TemplateData    helixTemplate.TemplateData `json:"template_data"`
+}
+
+// File asks Helix to render a template into the project.
+// Exactly one of Template (inline text/template) or Source (path under TemplateDir) is set.
+type File struct {
+	Dest      string `json:"dest"` // Relative to the project root
+	Source    string `json:"source,omitempty"`
+	Template  string `json:"template,omitempty"`
+	Overwrite bool   `json:"overwrite,omitempty"`
+}
+
+// Response is read as JSON from the plugin's stdout. An empty stdout means "nothing to apply".
+type Response struct {
+	ProtocolVersion int                  `json:"protocol_version"`
+	TemplateDir     string               `json:"template_dir,omitempty"`
+	Vars            map[string]string    `json:"vars,omitempty"`
+	Files           []File               `json:"files,omitempty"`
+	Injections      []helixAst.Injection `json:"injections,omitempty"`
+	Messages        []string             `json:"messages,omitempty"`
+	Error           string               `json:"error,omitempty"`
+}
+
+// Result summarizes what Apply changed on disk.
+type Result struct {
+	Written  []string
+	Skipped  []string
+	Injected int
+}
+
+// Run executes the plugin with the request on stdin. Stderr is passed through untouched.
+func (p *Plugin) Run(req Request) (*Response, error) {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("encode plugin request: %w", err)
+	}
+
+	var stdout bytes.Buffer
+	c := exec.Command(p.Path, req.Args...)
+	c.Dir = req.Cwd
+	c.Stdin = bytes.NewReader(payload)
+	c.Stdout = &stdout
+	c.Stderr = os.Stderr
+	c.Env = append(os.Environ(), fmt.Sprintf("HELIX_PLUGIN_PROTOCOL=%d", ProtocolVersion))
+
+	if err := c.Run(); err != nil {
+		return nil, fmt.Errorf("plugin '%s' failed: %w", p.Name, err)
+	}
+
+	out := bytes.TrimSpace(stdout.Bytes())
+	if len(out) == 0 {
+		return &Response{ProtocolVersion: ProtocolVersion}, nil
+	}
+
+	var resp Response
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("plugin '%s' wrote invalid protocol JSON to stdout: %w", p.Name, err)
+	}
+	if resp.ProtocolVersion > ProtocolVersion {
+		return nil, fmt.Errorf("plugin '%s' speaks protocol v%d, this helix-cli supports up to v%d", p.Name, resp.ProtocolVersion, ProtocolVersion)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("plugin '%s': %s", p.Name, resp.Error)
+	}
+	return &resp, nil
+}
+
+// Apply renders the requested files through gen and performs main.go injections under root.
+func Apply(resp *Response, gen *helixTemplate.Generator, root string) (*Result, error) {
+	res := &Result{}
+
+	if len(resp.Vars) > 0 {
+		if gen.Data.Vars == nil {
+			gen.Data.Vars = make(map[string]string)
+		}
+		for k, v := range resp.Vars {
+			gen.Data.Vars[k] = v
+		}
+	}
+
+	for _, f := range resp.Files {
+		dest, err := safeJoin(root, f.Dest)
+		if err != nil {
+			return res, err
+		}
+		if _, err := os.Stat(dest); err == nil && !f.Overwrite {
+			res.Skipped = append(res.Skipped, f.Dest)
+			continue
+		}
+
+		switch {
+		case f.Template != "":
+			err = gen.RenderToFile(f.Dest, []byte(f.Template), dest)
+		case f.Source != "":
+			if resp.TemplateDir == "" {
+				return res, fmt.Errorf("file '%s' uses source but the plugin did not set template_dir", f.Dest)
+			}
+			var content []byte
+			content, err = os.ReadFile(filepath.Join(resp.TemplateDir, f.Source))
+			if err == nil {
+				err = gen.RenderToFile(f.Source, content, dest)
+			}
+		default:
+			err = fmt.Errorf("file '%s' has neither template nor source", f.Dest)
+		}
+		if err != nil {
+			return res, err
+		}
+		res.Written = append(res.Written, f.Dest)
+	}
+
+	if len(resp.Injections) > 0 {
+		injections := make([]helixAst.Injection, len(resp.Injections))
+		for i, inj := range resp.Injections {
+			code, err := gen.Render("injection:"+inj.Point, []byte(inj.Code))
+			if err != nil {
+				return res, err
+			}
+			inj.Code = string(code)
+			injections[i] = inj
+		}
+
+		n, err := helixAst.NewInjector(filepath.Join(root, MainFile)).Apply(injections)
+		if err != nil {
+			return res, err
+		}
+		res.Injected = n
+	}
+
+	return res, nil
+}
+
+func safeJoin(root, rel string) (string, error) {
+	if filepath.IsAbs(rel) {
+		return "", fmt.Errorf("plugin dest must be relative to the project root: %s", rel)
+	}
+	dest := filepath.Join(root, rel)
+	if r, err := filepath.Rel(root, dest); err != nil || strings.HasPrefix(r, "..") {
+		return "", fmt.Errorf("plugin dest escapes the project root: %s", rel)
+	}
+	return dest, nil
+}
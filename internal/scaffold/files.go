@@ -0,0 +1,338 @@
+package scaffold
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/godamri/helix-cli/internal/cron"
+	"github.com/godamri/helix-cli/internal/manifest"
+)
+
+// Template paths of the single-file generators.
+const (
+	ConsumerTemplate         = "templates/consumer/consumer.go.tmpl"
+	ConsumerRegistryTemplate = "templates/consumer/registry.go.tmpl"
+	CacheTemplate            = "templates/cache/cache.go.tmpl"
+	CachedRepositoryTemplate = "templates/repository/cached_repository.go.tmpl"
+	OutboxPartitionTemplate  = "templates/outbox/partitioning.sql.tmpl"
+	SagaTemplate             = "templates/saga/saga.go.tmpl"
+	SagaTestTemplate         = "templates/saga/saga_test.go.tmpl"
+	JobTemplate              = "templates/job/job.go.tmpl"
+	JobRegistryTemplate      = "templates/job/registry.go.tmpl"
+)
+
+// ServiceFiles maps every template rendered by 'init' to its destination under dest.
+// entityFile is the snake_case file name of the initial entity.
+func ServiceFiles(dest, entityFile string) map[string]string {
+	return map[string]string{
+		"templates/Makefile":                                         filepath.Join(dest, "Makefile"),
+		"templates/Dockerfile.tmpl":                                  filepath.Join(dest, "Dockerfile"),
+		"templates/.air.toml":                                        filepath.Join(dest, ".air.toml"),
+		"templates/docker-compose.yml":                               filepath.Join(dest, "docker-compose.yml"),
+		"templates/docker-compose.infra.yml":                         filepath.Join(dest, "docker-compose.infra.yml"),
+		"templates/.env":                                             filepath.Join(dest, ".env"),
+		"templates/.golangci.yml":                                    filepath.Join(dest, ".golangci.yml"),
+		"templates/buf.gen.yaml":                                     filepath.Join(dest, "buf.gen.yaml"),
+		"templates/api/proto/v1/service.proto":                       filepath.Join(dest, "api", "proto", "v1", fmt.Sprintf("%s.proto", entityFile)),
+		"templates/api/proto/v1/outbox_admin.proto":                  filepath.Join(dest, "api", "proto", "v1", "outbox_admin.proto"),
+		"templates/app/cmd/server/main.go.tmpl":                      filepath.Join(dest, "cmd", "server", "main.go"),
+		"templates/app/docs/asyncapi.go.tmpl":                        filepath.Join(dest, "docs", "asyncapi.go"),
+		"templates/app/go.mod.tmpl":                                  filepath.Join(dest, "go.mod"),
+		"templates/app/internal/pkg/config/config.go.tmpl":           filepath.Join(dest, "internal", "pkg", "config", "config.go"),
+		"templates/app/internal/pkg/config/retry.go.tmpl":            filepath.Join(dest, "internal", "pkg", "config", "retry.go"),
+		"templates/app/internal/pkg/config/retry_test.go.tmpl":       filepath.Join(dest, "internal", "pkg", "config", "retry_test.go"),
+		"templates/app/internal/pkg/config/consumers.go.tmpl":        filepath.Join(dest, "internal", "pkg", "config", "consumers.go"),
+		"templates/app/ent/entc.go.tmpl":                             filepath.Join(dest, "ent", "entc.go"),
+		"templates/app/ent/generate.go.tmpl":                         filepath.Join(dest, "ent", "generate.go"),
+		"templates/entity/ent_schema.go.tmpl":                        filepath.Join(dest, "ent", "schema", fmt.Sprintf("%s.go", entityFile)),
+		"templates/app/ent/schema/outbox.go.tmpl":                    filepath.Join(dest, "ent", "schema", "outbox.go"),
+		"templates/app/ent/schema/inbox.go.tmpl":                     filepath.Join(dest, "ent", "schema", "inbox.go"),
+		"templates/app/ent/schema/dead_letter.go.tmpl":               filepath.Join(dest, "ent", "schema", "dead_letter.go"),
+		"templates/entity/port_service.go.tmpl":                      filepath.Join(dest, "internal", "core", "port", fmt.Sprintf("%s_service.go", entityFile)),
+		"templates/entity/port_repository.go.tmpl":                   filepath.Join(dest, "internal", "core", "port", fmt.Sprintf("%s_repository.go", entityFile)),
+		"templates/app/internal/core/port/transaction.go.tmpl":       filepath.Join(dest, "internal", "core", "port", "transaction.go"),
+		"templates/app/internal/core/port/outbox_repository.go.tmpl": filepath.Join(dest, "internal", "core", "port", "outbox_repository.go"),
+		"templates/app/internal/core/port/outbox_admin.go.tmpl":      filepath.Join(dest, "internal", "core", "port", "outbox_admin.go"),
+		"templates/app/internal/core/port/inbox.go.tmpl":             filepath.Join(dest, "internal", "core", "port", "inbox.go"),
+		"templates/app/internal/core/port/dead_letter.go.tmpl":       filepath.Join(dest, "internal", "core", "port", "dead_letter.go"),
+		"templates/entity/entity.go.tmpl":                            filepath.Join(dest, "internal", "core", "entity", fmt.Sprintf("%s.go", entityFile)),
+		"templates/app/internal/core/event/event.go.tmpl":            filepath.Join(dest, "internal", "core", "event", "event.go"),
+		"templates/entity/event.go.tmpl":                             filepath.Join(dest, "internal", "core", "event", fmt.Sprintf("%s.go", entityFile)),
+
+		// DTO -> V1
+		"templates/entity/dto.go.tmpl":                            filepath.Join(dest, "internal", "core", "dto", "v1", fmt.Sprintf("%s.go", entityFile)),
+		"templates/app/internal/core/dto/v1/common.go.tmpl":       filepath.Join(dest, "internal", "core", "dto", "v1", "common.go"),
+		"templates/app/internal/core/dto/v1/outbox_admin.go.tmpl": filepath.Join(dest, "internal", "core", "dto", "v1", "outbox_admin.go"),
+		"templates/app/internal/core/dto/v1/dlq_admin.go.tmpl":    filepath.Join(dest, "internal", "core", "dto", "v1", "dlq_admin.go"),
+
+		"templates/entity/service_impl.go.tmpl":                                     filepath.Join(dest, "internal", "core", "service", fmt.Sprintf("%s_service.go", entityFile)),
+		"templates/entity/repo_impl.go.tmpl":                                        filepath.Join(dest, "internal", "adapter", "repository", fmt.Sprintf("%s_repository.go", entityFile)),
+		"templates/app/internal/adapter/repository/transaction.go.tmpl":             filepath.Join(dest, "internal", "adapter", "repository", "transaction.go"),
+		"templates/app/internal/adapter/repository/outbox_repository.go.tmpl":       filepath.Join(dest, "internal", "adapter", "repository", "outbox_repository.go"),
+		"templates/app/internal/adapter/repository/outbox_admin_repository.go.tmpl": filepath.Join(dest, "internal", "adapter", "repository", "outbox_admin_repository.go"),
+		"templates/app/internal/adapter/repository/inbox_repository.go.tmpl":        filepath.Join(dest, "internal", "adapter", "repository", "inbox_repository.go"),
+		"templates/app/internal/adapter/repository/dead_letter_repository.go.tmpl":  filepath.Join(dest, "internal", "adapter", "repository", "dead_letter_repository.go"),
+
+		// Handlers -> V1
+		"templates/entity/handler_impl.go.tmpl":      filepath.Join(dest, "internal", "adapter", "handler", "v1", fmt.Sprintf("%s_handler.go", entityFile)),
+		"templates/entity/grpc_handler_impl.go.tmpl": filepath.Join(dest, "internal", "adapter", "handler", "v1", fmt.Sprintf("%s_grpc_handler.go", entityFile)),
+
+		"templates/app/internal/core/entity/errors.go.tmpl":              filepath.Join(dest, "internal", "core", "entity", "errors.go"),
+		"templates/app/internal/adapter/worker/outbox.go.tmpl":           filepath.Join(dest, "internal", "adapter", "worker", "outbox.go"),
+		"templates/app/internal/adapter/worker/outbox_listener.go.tmpl":  filepath.Join(dest, "internal", "adapter", "worker", "outbox_listener.go"),
+		"templates/app/internal/adapter/worker/outbox_retention.go.tmpl": filepath.Join(dest, "internal", "adapter", "worker", "outbox_retention.go"),
+		"templates/app/internal/adapter/worker/outbox_retry.go.tmpl":     filepath.Join(dest, "internal", "adapter", "worker", "outbox_retry.go"),
+		"templates/app/internal/adapter/worker/leader.go.tmpl":           filepath.Join(dest, "internal", "adapter", "worker", "leader.go"),
+		"templates/app/internal/adapter/worker/leader_test.go.tmpl":      filepath.Join(dest, "internal", "adapter", "worker", "leader_test.go"),
+		"templates/app/internal/adapter/worker/inbox.go.tmpl":            filepath.Join(dest, "internal", "adapter", "worker", "inbox.go"),
+		"templates/app/internal/adapter/worker/consumer.go.tmpl":         filepath.Join(dest, "internal", "adapter", "worker", "consumer.go"),
+		"templates/app/internal/adapter/worker/dlq.go.tmpl":              filepath.Join(dest, "internal", "adapter", "worker", "dlq.go"),
+		"templates/app/internal/adapter/worker/dlq_test.go.tmpl":         filepath.Join(dest, "internal", "adapter", "worker", "dlq_test.go"),
+		"templates/app/internal/adapter/worker/inbox_test.go.tmpl":       filepath.Join(dest, "internal", "adapter", "worker", "inbox_test.go"),
+
+		"templates/app/internal/adapter/producer/producer.go.tmpl":     filepath.Join(dest, "internal", "adapter", "producer", "producer.go"),
+		"templates/app/internal/adapter/producer/kafka.go.tmpl":        filepath.Join(dest, "internal", "adapter", "producer", "kafka.go"),
+		"templates/app/internal/adapter/producer/nats.go.tmpl":         filepath.Join(dest, "internal", "adapter", "producer", "nats.go"),
+		"templates/app/internal/adapter/producer/rabbitmq.go.tmpl":     filepath.Join(dest, "internal", "adapter", "producer", "rabbitmq.go"),
+		"templates/app/internal/adapter/producer/redis_stream.go.tmpl": filepath.Join(dest, "internal", "adapter", "producer", "redis_stream.go"),
+		"templates/app/internal/adapter/producer/webhook.go.tmpl":      filepath.Join(dest, "internal", "adapter", "producer", "webhook.go"),
+
+		"templates/app/internal/adapter/handler/validation.go.tmpl":        filepath.Join(dest, "internal", "adapter", "handler", "v1", "validation.go"),
+		"templates/app/internal/adapter/handler/outbox_admin.go.tmpl":      filepath.Join(dest, "internal", "adapter", "handler", "v1", "outbox_admin_handler.go"),
+		"templates/app/internal/adapter/handler/outbox_admin_grpc.go.tmpl": filepath.Join(dest, "internal", "adapter", "handler", "v1", "outbox_admin_grpc_handler.go"),
+		"templates/app/internal/adapter/handler/dlq_admin.go.tmpl":         filepath.Join(dest, "internal", "adapter", "handler", "v1", "dlq_admin_handler.go"),
+
+		"templates/app/internal/pkg/cloudevents/cloudevents.go.tmpl": filepath.Join(dest, "internal", "pkg", "cloudevents", "cloudevents.go"),
+		"templates/app/internal/pkg/middleware/deprecation.go.tmpl":  filepath.Join(dest, "internal", "pkg", "middleware", "deprecation.go"),
+		"templates/app/internal/pkg/middleware/admin_auth.go.tmpl":   filepath.Join(dest, "internal", "pkg", "middleware", "admin_auth.go"),
+
+		"templates/app/tests/integration/setup_test.go.tmpl":     filepath.Join(dest, "tests", "integration", "setup_test.go"),
+		"templates/app/tests/integration/outbox_tx_test.go.tmpl": filepath.Join(dest, "tests", "integration", "outbox_tx_test.go"),
+		"templates/app/migrations/.keep":                         filepath.Join(dest, "migrations", ".keep"),
+		"templates/.github/workflows/ci.yml":                     filepath.Join(dest, ".github", "workflows", "ci.yml"),
+
+		"templates/app/internal/core/port/database.go.tmpl":   filepath.Join(dest, "internal", "core", "port", "database.go"),
+		"templates/app/internal/pkg/telemetry/logger.go.tmpl": filepath.Join(dest, "internal", "pkg", "telemetry", "logger.go"),
+		"templates/app/internal/pkg/middleware/audit.go.tmpl": filepath.Join(dest, "internal", "pkg", "middleware", "audit.go"),
+	}
+}
+
+// EntityFiles maps the templates rendered by 'new entity' to their destinations under root.
+func EntityFiles(root, entityFile string) map[string]string {
+	return map[string]string{
+		"templates/entity/entity.go.tmpl": filepath.Join(root, "internal", "core", "entity", fmt.Sprintf("%s.go", entityFile)),
+		"templates/entity/event.go.tmpl":  filepath.Join(root, "internal", "core", "event", fmt.Sprintf("%s.go", entityFile)),
+		// DTO v1
+		"templates/entity/dto.go.tmpl":             filepath.Join(root, "internal", "core", "dto", "v1", fmt.Sprintf("%s.go", entityFile)),
+		"templates/entity/port_service.go.tmpl":    filepath.Join(root, "internal", "core", "port", fmt.Sprintf("%s_service.go", entityFile)),
+		"templates/entity/port_repository.go.tmpl": filepath.Join(root, "internal", "core", "port", fmt.Sprintf("%s_repository.go", entityFile)),
+		"templates/entity/service_impl.go.tmpl":    filepath.Join(root, "internal", "core", "service", fmt.Sprintf("%s_service.go", entityFile)),
+		"templates/entity/repo_impl.go.tmpl":       filepath.Join(root, "internal", "adapter", "repository", fmt.Sprintf("%s_repository.go", entityFile)),
+		// Handler v1
+		"templates/entity/handler_impl.go.tmpl": filepath.Join(root, "internal", "adapter", "handler", "v1", fmt.Sprintf("%s_handler.go", entityFile)),
+		"templates/entity/ent_schema.go.tmpl":   filepath.Join(root, "ent", "schema", fmt.Sprintf("%s.go", entityFile)),
+	}
+}
+
+// WorkspaceFiles maps the templates rendered by 'workspace init' to their destinations under root.
+func WorkspaceFiles(root string) map[string]string {
+	return map[string]string{
+		"templates/workspace/Makefile":       filepath.Join(root, "Makefile"),
+		"templates/workspace/buf.yaml":       filepath.Join(root, "api", "proto", "buf.yaml"),
+		"templates/docker-compose.infra.yml": filepath.Join(root, "docker-compose.infra.yml"),
+	}
+}
+
+// SagaRuntimeFiles maps the templates of the saga runtime, rendered by the first
+// 'new saga' of a service, to their destinations under root.
+func SagaRuntimeFiles(root string) map[string]string {
+	return map[string]string{
+		"templates/saga/schema.go.tmpl":       filepath.Join(root, "ent", "schema", "saga.go"),
+		"templates/saga/port.go.tmpl":         filepath.Join(root, "internal", "core", "port", "saga.go"),
+		"templates/saga/repository.go.tmpl":   filepath.Join(root, "internal", "adapter", "repository", "saga_repository.go"),
+		"templates/saga/runtime.go.tmpl":      filepath.Join(root, "internal", "adapter", "worker", "saga.go"),
+		"templates/saga/harness_test.go.tmpl": filepath.Join(root, "internal", "adapter", "worker", "saga_harness_test.go"),
+	}
+}
+
+// SagaFiles maps the templates of one saga to their destinations under root.
+// sagaFile is its snake_case file name.
+func SagaFiles(root, sagaFile string) map[string]string {
+	worker := filepath.Join(root, "internal", "adapter", "worker")
+	return map[string]string{
+		SagaTemplate:     filepath.Join(worker, fmt.Sprintf("saga_%s.go", sagaFile)),
+		SagaTestTemplate: filepath.Join(worker, fmt.Sprintf("saga_%s_test.go", sagaFile)),
+	}
+}
+
+// JobRuntimeFiles maps the templates of the job scheduler, rendered by the first
+// 'new job' of a service, to their destinations under root.
+func JobRuntimeFiles(root string) map[string]string {
+	worker := filepath.Join(root, "internal", "adapter", "worker")
+	return map[string]string{
+		"templates/job/schema.go.tmpl":         filepath.Join(root, "ent", "schema", "job_run.go"),
+		"templates/job/port.go.tmpl":           filepath.Join(root, "internal", "core", "port", "job_run.go"),
+		"templates/job/repository.go.tmpl":     filepath.Join(root, "internal", "adapter", "repository", "job_run_repository.go"),
+		"templates/job/scheduler.go.tmpl":      filepath.Join(worker, "scheduler.go"),
+		"templates/job/scheduler_test.go.tmpl": filepath.Join(worker, "scheduler_test.go"),
+		"templates/job/cron.go.tmpl":           filepath.Join(worker, "cron.go"),
+		"templates/job/cron_test.go.tmpl":      filepath.Join(worker, "cron_test.go"),
+	}
+}
+
+// JobData is the data of JobTemplate and the job runtime.
+type JobData struct {
+	GoModuleName string
+	Name         string
+}
+
+// JobRegistryData is the data of JobRegistryTemplate.
+type JobRegistryData struct {
+	GoModuleName string
+	Jobs         []RegisteredJob
+}
+
+// RegisteredJob is a manifest job with its timeout as a Go expression.
+type RegisteredJob struct {
+	Name     string
+	Schedule string
+	Timeout  string
+}
+
+// NewJobRegistryData validates the manifest jobs for rendering.
+func NewJobRegistryData(module string, jobs []manifest.Job) (JobRegistryData, error) {
+	data := JobRegistryData{GoModuleName: module}
+	for _, j := range jobs {
+		if _, err := cron.Parse(j.Schedule); err != nil {
+			return data, fmt.Errorf("job %s: %w", j.Name, err)
+		}
+		if d, err := time.ParseDuration(j.Timeout); j.Timeout == "" || err == nil && d <= 0 {
+			return data, fmt.Errorf("job %s: timeout must be positive", j.Name)
+		}
+		timeout, err := durationExpr(j.Timeout)
+		if err != nil {
+			return data, fmt.Errorf("job %s: timeout: %w", j.Name, err)
+		}
+		data.Jobs = append(data.Jobs, RegisteredJob{Name: j.Name, Schedule: j.Schedule, Timeout: timeout})
+	}
+	return data, nil
+}
+
+// ConsumerData is the data of ConsumerTemplate.
+type ConsumerData struct {
+	EntityName   string
+	Topic        string
+	GoModuleName string
+	Event        string // Contract type in internal/core/event; "" declares a local payload struct
+}
+
+// ConsumerRegistryData is the data of ConsumerRegistryTemplate.
+type ConsumerRegistryData struct {
+	GoModuleName string
+	Consumers    []RegisteredConsumer
+}
+
+// RegisteredConsumer is a manifest consumer with its durations as Go expressions.
+type RegisteredConsumer struct {
+	Name           string
+	Topic          string
+	Group          string
+	MaxRetries     int
+	InitialBackoff string
+	MaxBackoff     string
+	DLQ            string
+	Concurrency    int
+	HashIDs        bool
+}
+
+// NewConsumerRegistryData validates the manifest consumers for rendering.
+func NewConsumerRegistryData(module string, consumers []manifest.Consumer) (ConsumerRegistryData, error) {
+	data := ConsumerRegistryData{GoModuleName: module}
+	for _, c := range consumers {
+		initial, err := durationExpr(c.InitialBackoff)
+		if err != nil {
+			return data, fmt.Errorf("consumer %s: initial_backoff: %w", c.Name, err)
+		}
+		max, err := durationExpr(c.MaxBackoff)
+		if err != nil {
+			return data, fmt.Errorf("consumer %s: max_backoff: %w", c.Name, err)
+		}
+		data.Consumers = append(data.Consumers, RegisteredConsumer{
+			Name:           c.Name,
+			Topic:          c.Topic,
+			Group:          c.Group,
+			MaxRetries:     c.MaxRetries,
+			InitialBackoff: initial,
+			MaxBackoff:     max,
+			DLQ:            c.DLQ,
+			Concurrency:    c.Concurrency,
+			HashIDs:        c.HashIDs,
+		})
+	}
+	return data, nil
+}
+
+// durationExpr renders a duration string as a Go expression, e.g. "1m30s" as
+// 90 * time.Second.
+func durationExpr(s string) (string, error) {
+	if s == "" {
+		s = "0s"
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case d < 0:
+		return "", fmt.Errorf("%s is negative", s)
+	case d == 0:
+		return "time.Duration(0)", nil
+	}
+	units := []struct {
+		d    time.Duration
+		name string
+	}{{time.Hour, "Hour"}, {time.Minute, "Minute"}, {time.Second, "Second"}, {time.Millisecond, "Millisecond"}}
+	for _, u := range units {
+		if d%u.d == 0 {
+			return fmt.Sprintf("%d * time.%s", d/u.d, u.name), nil
+		}
+	}
+	return fmt.Sprintf("time.Duration(%d)", d), nil
+}
+
+// SagaData is the data of the saga templates. The runtime ones only use GoModuleName.
+type SagaData struct {
+	GoModuleName string
+	Name         string
+	ReplyTopic   string
+	Steps        []SagaStepData
+}
+
+// SagaStepData is a step of SagaData with its timeout as a Go expression.
+type SagaStepData struct {
+	Name            string
+	Topic           string
+	CompensateTopic string
+	Timeout         string
+}
+
+// NewSagaStep validates a step for rendering.
+func NewSagaStep(name, topic string, timeout time.Duration) (SagaStepData, error) {
+	expr, err := durationExpr(timeout.String())
+	if err != nil {
+		return SagaStepData{}, fmt.Errorf("step %s: timeout: %w", name, err)
+	}
+	return SagaStepData{Name: name, Topic: topic, CompensateTopic: topic + ".compensate", Timeout: expr}, nil
+}
+
+// CacheData is the data of CacheTemplate.
+type CacheData struct {
+	StructName      string
+	LowerStructName string
+}
+
+// OutboxPartitionData is the data of OutboxPartitionTemplate.
+type OutboxPartitionData struct {
+	PremakeDays int // Daily partitions created ahead of today
+}
@@ -20,6 +20,16 @@ type TemplateData struct {
 	EntityNameLower   string
 	EntityPluralLower string
 	Driver            string
+	Broker            string // Outbox event broker; empty when rendering workspace files
+
+	// InfraComposeFile is the shared infra compose path, relative to the service root.
+	InfraComposeFile string
+	// SharedProtoDir is the workspace api/proto, relative to the service root;
+	// empty outside a workspace.
+	SharedProtoDir string
+
+	// Vars carries free-form values from plugins, available as {{ .Vars.key }}.
+	Vars map[string]string
 }
 
 type Generator struct {
@@ -42,16 +52,15 @@ func (g *Generator) ProcessFile(sourcePath, destPath string) error {
 		return fmt.Errorf("read template '%s': %w", sourcePath, err)
 	}
 
-	// Parse Template
-	tmpl, err := template.New(filepath.Base(sourcePath)).Parse(string(content))
-	if err != nil {
-		return fmt.Errorf("parse template '%s': %w", sourcePath, err)
-	}
+	return g.RenderToFile(sourcePath, content, destPath)
+}
 
-	// Execute
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, g.Data); err != nil {
-		return fmt.Errorf("execute template '%s': %w", sourcePath, err)
+// RenderToFile executes an in-memory template with Data and writes the result to dest.
+// name is only used for error messages.
+func (g *Generator) RenderToFile(name string, content []byte, destPath string) error {
+	out, err := g.Render(name, content)
+	if err != nil {
+		return err
 	}
 
 	// Write to Disk
@@ -59,5 +68,27 @@ func (g *Generator) ProcessFile(sourcePath, destPath string) error {
 		return fmt.Errorf("create dir: %w", err)
 	}
 
-	return os.WriteFile(destPath, buf.Bytes(), 0644)
+	return os.WriteFile(destPath, out, 0644)
+}
+
+// Render executes an in-memory template with Data and returns the output.
+func (g *Generator) Render(name string, content []byte) ([]byte, error) {
+	return Execute(name, content, g.Data)
+}
+
+// Execute parses and executes a template with arbitrary data.
+// Generators with their own data shape (consumer, cache) use it directly.
+func Execute(name string, content []byte, data interface{}) ([]byte, error) {
+	// Parse Template
+	tmpl, err := template.New(filepath.Base(name)).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("parse template '%s': %w", name, err)
+	}
+
+	// Execute
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("execute template '%s': %w", name, err)
+	}
+	return buf.Bytes(), nil
 }
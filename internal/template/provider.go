@@ -26,29 +26,72 @@ func (e *EmbeddedFetcher) Walk(root string, fn fs.WalkDirFunc) error {
 	return fs.WalkDir(e.FS, root, fn)
 }
 
+// Template sources, in lookup order.
+const (
+	SourceTemplateDir = "template-dir" // --template-dir
+	SourceProject     = "project"      // <project>/.helix/templates
+	SourceUser        = "user"         // ~/.helix/templates
+	SourceEmbedded    = "embedded"
+)
+
+// Layer is a directory of template overrides. Dir mirrors the embedded layout,
+// so an override of "templates/entity/dto.go.tmpl" is Dir/templates/entity/dto.go.tmpl.
+type Layer struct {
+	Source string
+	Dir    string
+}
+
 // SmartFetcher implements "Local-Override, Embed-Default" strategy.
+// Layers are searched in order; the first one holding a file wins, then Embedded.
 type SmartFetcher struct {
 	Embedded *EmbeddedFetcher
-	LocalDir string
+	Layers   []Layer
 	Logger   *slog.Logger
 }
 
-func NewSmartFetcher(embeddedFS fs.FS, logger *slog.Logger) *SmartFetcher {
+// UserDir is the per-user override directory, ~/.helix/templates.
+func UserDir() string {
 	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".helix", "templates")
+}
+
+func NewSmartFetcher(embeddedFS fs.FS, logger *slog.Logger) *SmartFetcher {
 	return &SmartFetcher{
 		Embedded: &EmbeddedFetcher{FS: embeddedFS},
-		LocalDir: filepath.Join(home, ".helix", "templates"),
+		Layers:   []Layer{{Source: SourceUser, Dir: UserDir()}},
 		Logger:   logger,
 	}
 }
 
+// Prepend adds a layer that takes precedence over the existing ones.
+func (s *SmartFetcher) Prepend(source, dir string) {
+	s.Layers = append([]Layer{{Source: source, Dir: dir}}, s.Layers...)
+}
+
+// Resolve reports where ReadFile reads path from: the layer source and file,
+// or SourceEmbedded and "".
+func (s *SmartFetcher) Resolve(path string) (source, file string) {
+	for _, l := range s.Layers {
+		file := filepath.Join(l.Dir, filepath.FromSlash(path))
+		if info, err := os.Stat(file); err == nil && !info.IsDir() {
+			return l.Source, file
+		}
+	}
+	return SourceEmbedded, ""
+}
+
+// Source is the source name half of Resolve.
+func (s *SmartFetcher) Source(path string) string {
+	source, _ := s.Resolve(path)
+	return source
+}
+
 func (s *SmartFetcher) ReadFile(path string) ([]byte, error) {
 	// Try Local Override
 	// Only check if file exists locally. No network calls.
-	localPath := filepath.Join(s.LocalDir, path)
-	if info, err := os.Stat(localPath); err == nil && !info.IsDir() {
-		s.Logger.Debug("Using local template override", "path", localPath)
-		return os.ReadFile(localPath)
+	if source, file := s.Resolve(path); file != "" {
+		s.Logger.Debug("Using local template override", "source", source, "path", file)
+		return os.ReadFile(file)
 	}
 
 	// Fallback to Embedded (Default/Safe)
@@ -0,0 +1,31 @@
+// Package uuid is a type-checking stub of github.com/google/uuid (API subset used by Helix templates).
+package uuid
+
+import "database/sql/driver"
+
+type UUID [16]byte
+
+type Version byte
+
+var Nil UUID
+
+func New() UUID                         { return Nil }
+func NewString() string                 { return "" }
+func NewRandom() (UUID, error)          { return Nil, nil }
+func NewV7() (UUID, error)              { return Nil, nil }
+func Must(uuid UUID, err error) UUID    { return uuid }
+func MustParse(s string) UUID           { return Nil }
+func Parse(s string) (UUID, error)      { return Nil, nil }
+func ParseBytes(b []byte) (UUID, error) { return Nil, nil }
+func FromBytes(b []byte) (UUID, error)  { return Nil, nil }
+func Validate(s string) error           { return nil }
+
+func (uuid UUID) String() string                     { return "" }
+func (uuid UUID) URN() string                        { return "" }
+func (uuid UUID) Version() Version                   { return 0 }
+func (uuid UUID) MarshalText() ([]byte, error)       { return nil, nil }
+func (uuid *UUID) UnmarshalText(data []byte) error   { return nil }
+func (uuid UUID) MarshalBinary() ([]byte, error)     { return nil, nil }
+func (uuid *UUID) UnmarshalBinary(data []byte) error { return nil }
+func (uuid UUID) Value() (driver.Value, error)       { return nil, nil }
+func (uuid *UUID) Scan(src interface{}) error        { return nil }
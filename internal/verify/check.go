@@ -0,0 +1,479 @@
+package verify
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/build/constraint"
+	"go/importer"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Hand-written stubs of the third-party packages whose API the templates use
+// most. Everything else outside the module and the standard library is opaque.
+//
+//go:embed _stubs
+var stubFS embed.FS
+
+// finding is a syntax or type error at a position in a rendered file.
+type finding struct {
+	kind string
+	pos  token.Position
+	msg  string
+}
+
+// checker owns what is shared between cases: the file set, the standard
+// library (type-checked from GOROOT source, no network or build cache needed)
+// and the stub packages.
+type checker struct {
+	fset  *token.FileSet
+	std   types.Importer
+	stubs map[string]*types.Package
+}
+
+func newChecker() *checker {
+	// The source importer can't process cgo files; the pure-Go fallbacks
+	// (net, os/user) type-check the same.
+	build.Default.CgoEnabled = false
+
+	fset := token.NewFileSet()
+	return &checker{
+		fset:  fset,
+		std:   importer.ForCompiler(fset, "source", nil),
+		stubs: map[string]*types.Package{},
+	}
+}
+
+func (c *checker) stub(importPath string) (*types.Package, bool, error) {
+	if pkg, ok := c.stubs[importPath]; ok {
+		return pkg, true, nil
+	}
+	dir := path.Join("_stubs", importPath)
+	entries, err := fs.ReadDir(stubFS, dir)
+	if err != nil {
+		return nil, false, nil
+	}
+
+	var files []*ast.File
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".go") {
+			continue
+		}
+		src, err := stubFS.ReadFile(path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, true, err
+		}
+		f, err := parser.ParseFile(c.fset, "stub:"+importPath+"/"+e.Name(), src, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, true, err
+		}
+		files = append(files, f)
+	}
+	pkg, err := (&types.Config{Importer: c.std}).Check(importPath, c.fset, files, nil)
+	if err != nil {
+		return nil, true, fmt.Errorf("stub %s: %w", importPath, err)
+	}
+	c.stubs[importPath] = pkg
+	return pkg, true, nil
+}
+
+// module type-checks one rendered Go module. It is the types.Importer for the
+// module's own packages.
+type module struct {
+	*checker
+	root, path string
+
+	pkgs   map[string]*types.Package // nil value: being checked (cycle guard)
+	opaque map[*types.Package]bool
+	names  map[string]string // Opaque import path -> package name
+	// Struct names embedding an opaque type; their promoted members are unknown
+	embedsOpaque map[string]bool
+	parsed       map[string][]*ast.File
+	findings     []finding
+	seen         map[string]bool
+}
+
+func (c *checker) checkModule(root, modulePath string) ([]finding, error) {
+	m := &module{
+		checker:      c,
+		root:         root,
+		path:         modulePath,
+		pkgs:         map[string]*types.Package{},
+		opaque:       map[*types.Package]bool{},
+		names:        map[string]string{},
+		embedsOpaque: map[string]bool{},
+		parsed:       map[string][]*ast.File{},
+		seen:         map[string]bool{},
+	}
+
+	var dirs []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if p != root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor") {
+				return filepath.SkipDir
+			}
+			rel, _ := filepath.Rel(root, p)
+			if generated(filepath.ToSlash(rel)) {
+				return nil
+			}
+			dirs = append(dirs, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dir := range dirs {
+		if err := m.parseDir(dir); err != nil {
+			return nil, err
+		}
+	}
+	for _, dir := range dirs {
+		m.checkDir(dir)
+	}
+
+	sort.Slice(m.findings, func(i, j int) bool {
+		a, b := m.findings[i].pos, m.findings[j].pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return m.findings, nil
+}
+
+// generated reports module directories that are produced by go generate
+// (ent, protoc) and so don't exist at template time. ent/schema is hand-written.
+func generated(rel string) bool {
+	if rel == "ent/schema" || strings.HasPrefix(rel, "ent/schema/") {
+		return false
+	}
+	return rel == "ent" || strings.HasPrefix(rel, "ent/")
+}
+
+// parseDir parses every Go file in dir that builds; syntax errors become findings.
+func (m *module) parseDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".go") {
+			continue
+		}
+		filename := filepath.Join(dir, e.Name())
+		f, err := parser.ParseFile(m.fset, filename, nil, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			var list scanner.ErrorList
+			if errors.As(err, &list) {
+				for _, e := range list {
+					m.add(finding{kind: KindSyntax, pos: e.Pos, msg: e.Msg})
+				}
+				continue
+			}
+			return err
+		}
+		if !builds(f) {
+			continue
+		}
+		m.parsed[dir] = append(m.parsed[dir], f)
+	}
+	return nil
+}
+
+// builds evaluates //go:build lines with every tag set except "ignore", so
+// integration-tagged tests are checked but go:generate programs are not.
+func builds(f *ast.File) bool {
+	for _, group := range f.Comments {
+		if group.Pos() > f.Package {
+			break
+		}
+		for _, c := range group.List {
+			if !constraint.IsGoBuild(c.Text) {
+				continue
+			}
+			expr, err := constraint.Parse(c.Text)
+			if err != nil {
+				return true
+			}
+			return expr.Eval(func(tag string) bool { return tag != "ignore" })
+		}
+	}
+	return true
+}
+
+func (m *module) Import(importPath string) (*types.Package, error) {
+	if pkg, ok := m.pkgs[importPath]; ok {
+		if pkg == nil {
+			return nil, fmt.Errorf("import cycle through %s", importPath)
+		}
+		return pkg, nil
+	}
+
+	var pkg *types.Package
+	switch {
+	case importPath == m.path || strings.HasPrefix(importPath, m.path+"/"):
+		dir := filepath.Join(m.root, filepath.FromSlash(strings.TrimPrefix(importPath, m.path)))
+		files := m.packageFiles(dir, false)
+		if len(files) == 0 {
+			// Generated (ent, protoc) or embedded-docs package
+			pkg = m.opaquePackage(importPath)
+			break
+		}
+		m.pkgs[importPath] = nil
+		pkg = m.checkFiles(importPath, files)
+	case isStd(importPath):
+		return m.std.Import(importPath)
+	default:
+		stub, ok, err := m.stub(importPath)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return stub, nil
+		}
+		pkg = m.opaquePackage(importPath)
+	}
+	m.pkgs[importPath] = pkg
+	return pkg, nil
+}
+
+// packageFiles returns the library files of dir, or the test files.
+func (m *module) packageFiles(dir string, tests bool) []*ast.File {
+	var out []*ast.File
+	for _, f := range m.parsed[dir] {
+		isTest := strings.HasSuffix(m.fset.File(f.Pos()).Name(), "_test.go")
+		if isTest == tests {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// checkDir checks the package in dir, then its in-package and external tests.
+func (m *module) checkDir(dir string) {
+	rel, _ := filepath.Rel(m.root, dir)
+	importPath := m.path
+	if rel != "." {
+		importPath += "/" + filepath.ToSlash(rel)
+	}
+
+	lib := m.packageFiles(dir, false)
+	if len(lib) > 0 {
+		if _, err := m.Import(importPath); err != nil {
+			m.add(finding{kind: KindType, pos: m.fset.Position(lib[0].Package), msg: err.Error()})
+		}
+	}
+
+	byName := map[string][]*ast.File{}
+	for _, f := range m.packageFiles(dir, true) {
+		byName[f.Name.Name] = append(byName[f.Name.Name], f)
+	}
+	for name, files := range byName {
+		if strings.HasSuffix(name, "_test") {
+			m.checkFiles(importPath+"_test", files)
+			continue
+		}
+		m.checkFiles(importPath, append(append([]*ast.File{}, lib...), files...))
+	}
+}
+
+func (m *module) checkFiles(importPath string, files []*ast.File) *types.Package {
+	for _, f := range files {
+		m.nameOpaqueImports(f)
+	}
+
+	var errs []types.Error
+	info := &types.Info{Uses: map[*ast.Ident]types.Object{}}
+	conf := types.Config{
+		Importer: m,
+		Error: func(err error) {
+			if terr, ok := err.(types.Error); ok {
+				errs = append(errs, terr)
+			}
+		},
+	}
+	pkg, _ := conf.Check(importPath, m.fset, files, info)
+
+	// Anything selected from an opaque package is unknown, not wrong.
+	var skip []struct{ from, to token.Pos }
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			if id, ok := sel.X.(*ast.Ident); ok {
+				if pn, ok := info.Uses[id].(*types.PkgName); ok && m.opaque[pn.Imported()] {
+					skip = append(skip, struct{ from, to token.Pos }{sel.Pos(), sel.End()})
+				}
+			}
+			return true
+		})
+	}
+
+	if pkg != nil {
+		m.noteOpaqueEmbeds(pkg)
+	}
+
+outer:
+	for _, e := range errs {
+		for _, s := range skip {
+			if e.Pos >= s.from && e.Pos < s.to {
+				continue outer
+			}
+		}
+		if m.cascade(e.Msg) {
+			continue
+		}
+		m.add(finding{kind: KindType, pos: m.fset.Position(e.Pos), msg: e.Msg})
+	}
+	return pkg
+}
+
+var missingMember = regexp.MustCompile(`\(type \*?(?:[\w.]+\.)?(\w+) has no field or method`)
+
+// cascade reports errors that only follow from an opaque package: values of
+// its (invalid) types, and members promoted from an embedded opaque type.
+// Tab-indented messages continue the previous error ("other declaration of").
+func (m *module) cascade(msg string) bool {
+	if strings.HasPrefix(msg, "\t") || strings.Contains(msg, "invalid type") {
+		return true
+	}
+	if match := missingMember.FindStringSubmatch(msg); match != nil {
+		return m.embedsOpaque[match[1]]
+	}
+	return false
+}
+
+// noteOpaqueEmbeds records the structs of pkg that embed an opaque type, e.g. a
+// test suite embedding suite.Suite.
+func (m *module) noteOpaqueEmbeds(pkg *types.Package) {
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		tn, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok {
+			continue
+		}
+		st, ok := tn.Type().Underlying().(*types.Struct)
+		if !ok {
+			continue
+		}
+		for i := 0; i < st.NumFields(); i++ {
+			f := st.Field(i)
+			if f.Embedded() && f.Type() == types.Typ[types.Invalid] {
+				m.embedsOpaque[name] = true
+			}
+		}
+	}
+}
+
+func (m *module) add(f finding) {
+	key := fmt.Sprintf("%s:%d:%d:%s", f.pos.Filename, f.pos.Line, f.pos.Column, f.msg)
+	if !m.seen[key] {
+		m.seen[key] = true
+		m.findings = append(m.findings, f)
+	}
+}
+
+// opaquePackage stands in for a package whose source isn't available.
+func (m *module) opaquePackage(importPath string) *types.Package {
+	name, ok := m.names[importPath]
+	if !ok {
+		name = guessName(importPath)
+	}
+	pkg := types.NewPackage(importPath, name)
+	pkg.MarkComplete()
+	m.opaque[pkg] = true
+	return pkg
+}
+
+// nameOpaqueImports decides the package name of unaliased imports that will be
+// opaque. The name is what the file uses as a selector base, e.g. "redis" for
+// github.com/redis/go-redis/v9; the last path element when that is ambiguous.
+func (m *module) nameOpaqueImports(f *ast.File) {
+	var pending []string
+	for _, spec := range f.Imports {
+		p := strings.Trim(spec.Path.Value, `"`)
+		if spec.Name != nil || isStd(p) || p == m.path || strings.HasPrefix(p, m.path+"/") {
+			continue
+		}
+		if _, ok := m.names[p]; !ok {
+			pending = append(pending, p)
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	bases := map[string]bool{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok {
+				bases[id.Name] = true
+			}
+		}
+		return true
+	})
+
+	for _, p := range pending {
+		name := guessName(p)
+		if !bases[name] {
+			// Longest selector base that appears in the path (host excluded)
+			flat := lastElems(strings.NewReplacer("-", "", "_", "", ".", "").Replace(strings.ToLower(p)))
+			best := ""
+			for base := range bases {
+				if len(base) > len(best) && len(base) > 2 && strings.Contains(flat, strings.ToLower(base)) {
+					best = base
+				}
+			}
+			if best != "" {
+				name = best
+			}
+		}
+		m.names[p] = name
+	}
+}
+
+// lastElems drops the host of an import path, so "github" never matches.
+func lastElems(p string) string {
+	if i := strings.Index(p, "/"); i >= 0 {
+		return p[i:]
+	}
+	return p
+}
+
+func guessName(importPath string) string {
+	elems := strings.Split(importPath, "/")
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = elems[len(elems)-2]
+	}
+	name = strings.TrimPrefix(strings.TrimSuffix(name, "-go"), "go-")
+	return strings.NewReplacer("-", "", ".", "").Replace(name)
+}
+
+func isStd(importPath string) bool {
+	first, _, _ := strings.Cut(importPath, "/")
+	return !strings.Contains(first, ".")
+}
@@ -0,0 +1,51 @@
+package verify
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+var templateAction = regexp.MustCompile(`{{.*?}}`)
+
+// templateLine maps a line of rendered output back to the template line that
+// produced it. Each template line becomes a pattern (actions match anything);
+// among the lines matching the rendered text, the one nearest to the rendered
+// line number wins. Returns 0 if nothing matches.
+func templateLine(tmpl, out []byte, line int) int {
+	outLines := bytes.Split(out, []byte("\n"))
+	if line < 1 || line > len(outLines) {
+		return 0
+	}
+	target := strings.TrimSpace(string(outLines[line-1]))
+	if target == "" {
+		return 0
+	}
+
+	best, bestDist := 0, -1
+	for i, raw := range strings.Split(string(tmpl), "\n") {
+		text := strings.TrimSpace(raw)
+		literal := strings.TrimSpace(templateAction.ReplaceAllString(text, ""))
+		if literal == "" {
+			continue // Pure action lines ({{ if }}, {{ end }}) render nothing useful
+		}
+
+		parts := templateAction.Split(text, -1)
+		for j, p := range parts {
+			parts[j] = regexp.QuoteMeta(p)
+		}
+		pattern, err := regexp.Compile(`^` + strings.Join(parts, `.*?`) + `$`)
+		if err != nil || !pattern.MatchString(target) {
+			continue
+		}
+
+		dist := i + 1 - line
+		if dist < 0 {
+			dist = -dist
+		}
+		if bestDist < 0 || dist < bestDist {
+			best, bestDist = i+1, dist
+		}
+	}
+	return best
+}
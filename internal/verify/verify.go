@@ -0,0 +1,316 @@
+// Package verify renders every template combination into a scratch module and
+// type-checks the result offline, so broken templates are caught before they ship.
+package verify
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/godamri/helix-cli/internal/manifest"
+	"github.com/godamri/helix-cli/internal/scaffold"
+	"github.com/godamri/helix-cli/internal/template"
+)
+
+// Issue kinds.
+const (
+	KindRender      = "render"      // text/template parse or execute error
+	KindPlaceholder = "placeholder" // "<no value>" in the output: a field the data doesn't have
+	KindSyntax      = "syntax"      // rendered Go doesn't parse
+	KindType        = "type"        // rendered Go doesn't type-check
+)
+
+// Case is one point of the option matrix: a service created by 'init', a second
+// entity added by 'new entity', plus the single-file generators.
+type Case struct {
+	Name      string                `json:"name"`
+	Driver    string                `json:"driver"`
+	Workspace bool                  `json:"workspace"`
+	Service   template.TemplateData `json:"-"`
+	Entity    template.TemplateData `json:"-"`
+}
+
+// Issue is a problem found in a rendered case, mapped back to its template.
+type Issue struct {
+	Case         string `json:"case"`
+	Kind         string `json:"kind"`
+	Template     string `json:"template"`
+	TemplateLine int    `json:"template_line,omitempty"` // 0 when the line couldn't be mapped back
+	File         string `json:"file,omitempty"`          // Rendered file, relative to the case directory
+	Line         int    `json:"line,omitempty"`
+	Col          int    `json:"col,omitempty"`
+	Message      string `json:"message"`
+}
+
+func (i Issue) String() string {
+	loc := i.Template
+	if i.TemplateLine > 0 {
+		loc += ":" + strconv.Itoa(i.TemplateLine)
+	}
+	if i.File == "" {
+		return fmt.Sprintf("%s [%s] %s: %s", loc, i.Case, i.Kind, i.Message)
+	}
+	return fmt.Sprintf("%s [%s] %s:%d:%d: %s", loc, i.Case, i.File, i.Line, i.Col, i.Message)
+}
+
+// Matrix returns the cases for the given drivers: each driver standalone and inside a workspace.
+func Matrix(drivers []string) []Case {
+	var cases []Case
+	for _, driver := range drivers {
+		for _, ws := range []bool{false, true} {
+			name := driver
+			infra, sharedProto := "docker-compose.infra.yml", ""
+			if ws {
+				name += "-workspace"
+				infra, sharedProto = "../docker-compose.infra.yml", "../api/proto"
+			}
+			svc := entityData("order", driver)
+			svc.ProjectName = "svc-order"
+			svc.GoModuleName = "github.com/godamri/svc-order"
+			svc.AppPort, svc.GrpcPort, svc.DBPort, svc.DBDevPort = 8080, 9090, 5432, 5433
+			svc.InfraComposeFile = infra
+			svc.SharedProtoDir = sharedProto
+			svc.Broker = "kafka"
+
+			// Multi-word name: catches casing mistakes a single word hides.
+			entity := entityData("user-profile", driver)
+			entity.GoModuleName = svc.GoModuleName
+
+			cases = append(cases, Case{Name: name, Driver: driver, Workspace: ws, Service: svc, Entity: entity})
+		}
+	}
+	return cases
+}
+
+// entityData mirrors the naming rules of 'init' and 'new entity'.
+func entityData(raw, driver string) template.TemplateData {
+	parts := strings.Split(raw, "-")
+	for i, p := range parts {
+		parts[i] = strings.ToUpper(p[:1]) + p[1:]
+	}
+	pascal := strings.Join(parts, "")
+	lower := strings.ToLower(pascal)
+	return template.TemplateData{
+		EntityName:        pascal,
+		EntityNameCamel:   strings.ToLower(pascal[:1]) + pascal[1:],
+		EntityNameLower:   lower,
+		EntityPluralLower: lower + "s",
+		Driver:            driver,
+	}
+}
+
+// Verifier renders and checks cases. One Verifier shares the parsed standard
+// library across cases, so reuse it for a whole matrix.
+type Verifier struct {
+	Fetcher template.Fetcher
+	Dir     string // Scratch directory; each case renders into Dir/<case name>
+
+	checker *checker
+}
+
+// New returns a Verifier reading templates through fetcher and rendering into dir.
+func New(fetcher template.Fetcher, dir string) *Verifier {
+	return &Verifier{Fetcher: fetcher, Dir: dir, checker: newChecker()}
+}
+
+// rendered remembers where a file came from, to map errors back.
+type rendered struct {
+	template string
+	source   []byte
+}
+
+// Run renders a case and returns every issue found in it.
+func (v *Verifier) Run(c Case) ([]Issue, error) {
+	caseDir := filepath.Join(v.Dir, c.Name)
+	root := caseDir
+	if c.Workspace {
+		root = filepath.Join(caseDir, c.Service.ProjectName)
+	}
+	if err := os.RemoveAll(caseDir); err != nil {
+		return nil, err
+	}
+
+	type job struct {
+		template, dest string
+		data           interface{}
+	}
+	var jobs []job
+	add := func(files map[string]string, data interface{}) {
+		for src, dest := range files {
+			jobs = append(jobs, job{src, dest, data})
+		}
+	}
+
+	serviceFiles := scaffold.ServiceFiles(root, fileName(c.Service.EntityNameCamel))
+	if c.Workspace {
+		delete(serviceFiles, "templates/docker-compose.infra.yml")
+		add(scaffold.WorkspaceFiles(caseDir), template.TemplateData{ProjectName: "platform"})
+	}
+	add(serviceFiles, c.Service)
+	add(scaffold.EntityFiles(root, fileName(c.Entity.EntityNameCamel)), c.Entity)
+
+	// The consumer init declares (typed) and one 'new consumer' adds (local payload, hash ids), plus their registry.
+	lower := strings.ToLower(c.Entity.EntityName)
+	worker := filepath.Join(root, "internal", "adapter", "worker")
+	consumers := map[string]manifest.Consumer{
+		filepath.Join(worker, "consumer_"+fileName(c.Service.EntityNameCamel)+"_created.go"): {
+			Name: c.Service.EntityName + "Created", Topic: c.Service.EntityNameLower + ".created", Event: c.Service.EntityName + "Created",
+			MaxRetries: 3, InitialBackoff: "1s", MaxBackoff: "5s", DLQ: c.Service.EntityNameLower + ".created.dlq", Concurrency: 1,
+		},
+		filepath.Join(worker, "consumer_"+fileName(c.Entity.EntityNameCamel)+"_events.go"): {
+			Name: c.Entity.EntityName, Topic: lower + ".events",
+			MaxRetries: 5, InitialBackoff: "500ms", MaxBackoff: "1m30s", Concurrency: 2, HashIDs: true,
+		},
+	}
+	var declared []manifest.Consumer
+	for dest, cons := range consumers {
+		add(map[string]string{scaffold.ConsumerTemplate: dest},
+			scaffold.ConsumerData{EntityName: cons.Name, Topic: cons.Topic, GoModuleName: c.Service.GoModuleName, Event: cons.Event})
+		declared = append(declared, cons)
+	}
+
+	// A saga, with a step that never times out, and its reply consumer.
+	saga := scaffold.SagaData{GoModuleName: c.Service.GoModuleName, Name: "OrderFulfillment", ReplyTopic: "order-fulfillment.replies"}
+	for _, s := range []struct {
+		name    string
+		timeout time.Duration
+	}{{"ReserveInventory", 30 * time.Second}, {"ChargePayment", 0}} {
+		step, err := scaffold.NewSagaStep(s.name, "order-fulfillment."+strings.ToLower(s.name), s.timeout)
+		if err != nil {
+			return nil, err
+		}
+		saga.Steps = append(saga.Steps, step)
+	}
+	add(scaffold.SagaRuntimeFiles(root), saga)
+	add(scaffold.SagaFiles(root, "order_fulfillment"), saga)
+	declared = append(declared, manifest.Consumer{Name: "OrderFulfillmentSaga", Topic: saga.ReplyTopic, MaxRetries: 3, Concurrency: 1})
+	sort.Slice(declared, func(i, j int) bool { return declared[i].Name < declared[j].Name })
+	registry, err := scaffold.NewConsumerRegistryData(c.Service.GoModuleName, declared)
+	if err != nil {
+		return nil, err
+	}
+	add(map[string]string{scaffold.ConsumerRegistryTemplate: filepath.Join(worker, "consumers_gen.go")}, registry)
+
+	// The scheduler with two jobs and their registry.
+	scheduled := []manifest.Job{
+		{Name: "ExpireSessions", Schedule: "*/5 * * * *", Timeout: "1m"},
+		{Name: "MonthlyInvoices", Schedule: "0 2 1 * *", Timeout: "30m"},
+	}
+	add(scaffold.JobRuntimeFiles(root), scaffold.JobData{GoModuleName: c.Service.GoModuleName})
+	for _, j := range scheduled {
+		add(map[string]string{scaffold.JobTemplate: filepath.Join(worker, "job_"+fileName(j.Name)+".go")},
+			scaffold.JobData{GoModuleName: c.Service.GoModuleName, Name: j.Name})
+	}
+	jobRegistry, err := scaffold.NewJobRegistryData(c.Service.GoModuleName, scheduled)
+	if err != nil {
+		return nil, err
+	}
+	add(map[string]string{scaffold.JobRegistryTemplate: filepath.Join(worker, "jobs_gen.go")}, jobRegistry)
+	add(map[string]string{
+		scaffold.CacheTemplate: filepath.Join(root, "internal", "adapter", "cache", "session_cache.go"),
+	}, scaffold.CacheData{StructName: "Session", LowerStructName: "session"})
+	add(map[string]string{
+		scaffold.CachedRepositoryTemplate: filepath.Join(root, "internal", "adapter", "repository", "cached_"+fileName(c.Service.EntityNameCamel)+"_repository.go"),
+	}, c.Service)
+	add(map[string]string{
+		scaffold.OutboxPartitionTemplate: filepath.Join(root, "migrations", "20240101000000_outbox_partitioning.sql"),
+	}, scaffold.OutboxPartitionData{PremakeDays: 3})
+
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].dest < jobs[j].dest })
+
+	var issues []Issue
+	seen := map[string]bool{}
+	files := map[string]rendered{}
+	for _, j := range jobs {
+		if strings.HasSuffix(j.template, ".keep") {
+			continue // Directory placeholders are written, not rendered
+		}
+		content, err := v.Fetcher.ReadFile(j.template)
+		if err != nil {
+			return nil, fmt.Errorf("read template '%s': %w", j.template, err)
+		}
+		out, err := template.Execute(j.template, content, j.data)
+		if err != nil {
+			// The same template fails the same way for both entities
+			if issue := renderIssue(c.Name, j.template, err); !seen[issue.String()] {
+				seen[issue.String()] = true
+				issues = append(issues, issue)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(j.dest), 0755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(j.dest, out, 0644); err != nil {
+			return nil, err
+		}
+
+		rel, _ := filepath.Rel(caseDir, j.dest)
+		for n, line := range bytes.Split(out, []byte("\n")) {
+			if col := bytes.Index(line, []byte("<no value>")); col >= 0 {
+				issues = append(issues, Issue{
+					Case: c.Name, Kind: KindPlaceholder, Template: j.template,
+					TemplateLine: templateLine(content, out, n+1),
+					File:         filepath.ToSlash(rel), Line: n + 1, Col: col + 1,
+					Message: "template printed <no value> (missing data field)",
+				})
+			}
+		}
+		files[j.dest] = rendered{template: j.template, source: content}
+	}
+
+	for _, issue := range issues {
+		if issue.Kind == KindRender {
+			// A file is missing; type errors would only echo that.
+			return issues, nil
+		}
+	}
+
+	found, err := v.checker.checkModule(root, c.Service.GoModuleName)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range found {
+		issue := Issue{Case: c.Name, Kind: f.kind, Line: f.pos.Line, Col: f.pos.Column, Message: f.msg}
+		rel, _ := filepath.Rel(caseDir, f.pos.Filename)
+		issue.File = filepath.ToSlash(rel)
+		if src, ok := files[f.pos.Filename]; ok {
+			issue.Template = src.template
+			out, _ := os.ReadFile(f.pos.Filename)
+			issue.TemplateLine = templateLine(src.source, out, f.pos.Line)
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+func fileName(camel string) string {
+	var b strings.Builder
+	for i, r := range camel {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// text/template errors look like "template: name.tmpl:12:5: executing ...".
+var templateErrLine = regexp.MustCompile(`template: [^:]+:(\d+)`)
+
+func renderIssue(caseName, tmpl string, err error) Issue {
+	issue := Issue{Case: caseName, Kind: KindRender, Template: tmpl, Message: err.Error()}
+	if m := templateErrLine.FindStringSubmatch(err.Error()); m != nil {
+		issue.TemplateLine, _ = strconv.Atoi(m[1])
+	}
+	return issue
+}
@@ -0,0 +1,118 @@
+package verify
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/godamri/helix-cli/internal/scaffold"
+	"github.com/godamri/helix-cli/internal/template"
+)
+
+// editedFetcher reads the templates of this repository, with some of them
+// rewritten.
+type editedFetcher struct {
+	*template.EmbeddedFetcher
+	edits map[string]func(string) string
+}
+
+func (f editedFetcher) ReadFile(path string) ([]byte, error) {
+	b, err := f.EmbeddedFetcher.ReadFile(path)
+	if edit, ok := f.edits[path]; ok && err == nil {
+		b = []byte(edit(string(b)))
+	}
+	return b, err
+}
+
+func newTestVerifier(t *testing.T, edits map[string]func(string) string) *Verifier {
+	t.Helper()
+	fetcher := editedFetcher{&template.EmbeddedFetcher{FS: os.DirFS("../..")}, edits}
+	return New(fetcher, t.TempDir())
+}
+
+func TestMatrix(t *testing.T) {
+	cases := Matrix([]string{"ent", "pgx"})
+	var names []string
+	for _, c := range cases {
+		names = append(names, c.Name)
+		if c.Workspace != (c.Service.SharedProtoDir != "") || c.Workspace != strings.HasPrefix(c.Service.InfraComposeFile, "../") {
+			t.Errorf("%s: workspace %t with shared protos %q and infra %q", c.Name, c.Workspace, c.Service.SharedProtoDir, c.Service.InfraComposeFile)
+		}
+		if c.Service.Driver != c.Driver || c.Entity.Driver != c.Driver {
+			t.Errorf("%s: drivers %s/%s, want %s", c.Name, c.Service.Driver, c.Entity.Driver, c.Driver)
+		}
+	}
+	if got, want := strings.Join(names, ","), "ent,ent-workspace,pgx,pgx-workspace"; got != want {
+		t.Errorf("cases = %s, want %s", got, want)
+	}
+	if e := cases[0].Entity; e.EntityName != "UserProfile" || e.EntityNameCamel != "userProfile" || e.EntityPluralLower != "userprofiles" {
+		t.Errorf("entity names = %s/%s/%s", e.EntityName, e.EntityNameCamel, e.EntityPluralLower)
+	}
+}
+
+func TestShippedTemplatesPass(t *testing.T) {
+	if testing.Short() {
+		t.Skip("type-checks a whole rendered service")
+	}
+	issues, err := newTestVerifier(t, nil).Run(Matrix([]string{"pgx"})[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, issue := range issues {
+		t.Error(issue)
+	}
+}
+
+func TestIssuesMapToTemplates(t *testing.T) {
+	if testing.Short() {
+		t.Skip("type-checks a whole rendered service")
+	}
+	tests := []struct {
+		name     string
+		edit     func(string) string
+		kind     string
+		line     int
+		contains string
+	}{
+		{
+			name: "missing data field",
+			edit: func(s string) string {
+				return strings.Replace(s, "type {{ .Name }}Job struct", "type {{ .Nope }}Job struct", 1)
+			},
+			kind:     KindRender,
+			line:     16,
+			contains: "Nope",
+		},
+		{
+			name:     "syntax error",
+			edit:     func(s string) string { return strings.Replace(s, "	return nil\n}\n", "	return nil\n", 1) },
+			kind:     KindSyntax,
+			contains: "expected",
+		},
+		{
+			name: "type error",
+			edit: func(s string) string {
+				return strings.Replace(s, "		svc:    svc,\n", "		svc:    svc,\n		nope:   1,\n", 1)
+			},
+			kind:     KindType,
+			line:     25,
+			contains: "nope",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := newTestVerifier(t, map[string]func(string) string{scaffold.JobTemplate: tt.edit})
+			issues, err := v.Run(Matrix([]string{"pgx"})[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, issue := range issues {
+				if issue.Template == scaffold.JobTemplate && issue.Kind == tt.kind && strings.Contains(issue.Message, tt.contains) &&
+					(tt.line == 0 || issue.TemplateLine == tt.line) {
+					return
+				}
+			}
+			t.Errorf("no %s issue at %s:%d mentioning %q in %v", tt.kind, scaffold.JobTemplate, tt.line, tt.contains, issues)
+		})
+	}
+}
@@ -12,14 +12,14 @@ import (
 // Use a wildcard to embed everything in templates/ recursively
 // This ensures .tmpl files are included without manual listing
 //
-//go:embed templates/Makefile templates/Dockerfile.tmpl templates/Dockerfile.migrate.tmpl templates/.air.toml templates/docker-compose.yml templates/docker-compose.infra.yml templates/.env templates/app templates/entity templates/app/go.mod.tmpl templates/buf.gen.yaml templates/api/proto/v1/service.proto templates/.golangci.yml templates/.github/workflows/ci.yml templates/app/scripts/gen-certs.sh templates/consumer/consumer.go.tmpl templates/cache/cache.go.tmpl
+//go:embed templates/Makefile templates/Dockerfile.tmpl templates/Dockerfile.migrate.tmpl templates/.air.toml templates/docker-compose.yml templates/docker-compose.infra.yml templates/.env templates/app templates/entity templates/app/go.mod.tmpl templates/buf.gen.yaml templates/api/proto/v1/service.proto templates/api/proto/v1/outbox_admin.proto templates/.golangci.yml templates/.github/workflows/ci.yml templates/consumer templates/saga templates/job templates/cache/cache.go.tmpl templates/outbox templates/repository templates/workspace templates/helix-pack.json
 var templateFS embed.FS
 
 func main() {
 	opts := &slog.HandlerOptions{
 		Level: slog.LevelInfo,
 	}
-	logger := slog.New(slog.NewTextHandler(os.Stdout, opts))
+	logger := slog.New(slog.NewTextHandler(os.Stderr, opts))
 	slog.SetDefault(logger)
 
 	// INJECT: Pass the embedded FS to the cmd package